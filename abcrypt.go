@@ -17,3 +17,19 @@ const HeaderSize = 148
 // TagSize is the number of bytes of the MAC (authentication tag) of the
 // ciphertext.
 const TagSize = chacha20poly1305.Overhead
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+
+	tail = head[len(in):]
+
+	return head, tail
+}
@@ -0,0 +1,416 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package agent implements an agent which holds a passphrase and the keys
+// derived from it, and a client which talks to the agent over a Unix domain
+// socket.
+//
+// Like ssh-agent, the agent lets a shell session enter the passphrase once,
+// and then serves encrypt, decrypt and verify requests without prompting and
+// without running Argon2 again for the same header. The passphrase and the
+// derived keys are held in locked memory and wiped when the TTL expires.
+//
+// The [Client] implements [abcrypt.KeyDeriver], so it can be plugged into the
+// library with [abcrypt.WithKeyDeriver].
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// EnvSocket is the name of the environment variable which holds the path of
+// the agent socket.
+const EnvSocket = "ABCRYPT_AGENT_SOCK"
+
+// DefaultTTL is the default lifetime of the passphrase held by the agent.
+const DefaultTTL = time.Hour
+
+const saltSize = 32
+
+// maxCachedKeys is the maximum number of derived keys held by the agent. Each
+// key uses a locked page, so this is bounded by RLIMIT_MEMLOCK.
+const maxCachedKeys = 256
+
+// maxRequestSize is the maximum size of a request line.
+const maxRequestSize = 256 << 20
+
+// PeerError represents an error due to the peer of a connection, either the
+// client or the agent, ran as a different user.
+type PeerError struct {
+	// UID represents the user ID of the peer.
+	UID int
+}
+
+// Error returns a string representation of a [PeerError].
+func (e *PeerError) Error() string {
+	return fmt.Sprintf("agent: rejected connection with uid %v", e.UID)
+}
+
+type lockedBuffer struct {
+	b []byte
+}
+
+type cacheKey struct {
+	salt        [saltSize]byte
+	argon2Type  abcrypt.Argon2Type
+	memoryCost  uint32
+	timeCost    uint32
+	parallelism uint32
+}
+
+// Agent holds a passphrase and the keys derived from it.
+type Agent struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	passphrase *lockedBuffer
+	timer      *time.Timer
+	keys       map[cacheKey]*lockedBuffer
+	order      []cacheKey
+	opts       []abcrypt.Option
+}
+
+// New creates a new [Agent] with the given default TTL.
+//
+// opts are passed to the encryptor and the decryptor, for example to limit
+// the memory used by concurrent requests with [abcrypt.WithMemoryBudget].
+func New(ttl time.Duration, opts ...abcrypt.Option) *Agent {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	a := Agent{ttl: ttl, keys: make(map[cacheKey]*lockedBuffer), opts: opts}
+
+	return &a
+}
+
+// Add makes the agent hold a copy of the passphrase for ttl, replacing the
+// previous one. If ttl is zero, the default TTL is used.
+func (a *Agent) Add(passphrase []byte, ttl time.Duration) error {
+	buf, err := newLockedBuffer(max(len(passphrase), 1))
+	if err != nil {
+		return err
+	}
+
+	buf.b = buf.b[:copy(buf.b, passphrase)]
+
+	if ttl <= 0 {
+		ttl = a.ttl
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lockLocked()
+	a.passphrase = buf
+	a.timer = time.AfterFunc(ttl, a.Lock)
+
+	return nil
+}
+
+// Lock wipes the passphrase and the derived keys held by the agent.
+func (a *Agent) Lock() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lockLocked()
+}
+
+func (a *Agent) lockLocked() {
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+
+	if a.passphrase != nil {
+		a.passphrase.destroy()
+		a.passphrase = nil
+	}
+
+	for k, buf := range a.keys {
+		buf.destroy()
+		delete(a.keys, k)
+	}
+
+	a.order = nil
+}
+
+// Serve accepts connections on l and serves requests until l is closed.
+//
+// Connections from other users are rejected where peer credentials are
+// available.
+func (a *Agent) Serve(l *net.UnixListener) error {
+	for {
+		conn, err := l.AcceptUnix()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		go a.serveConn(conn)
+	}
+}
+
+// DefaultSocketPath returns the default path of the agent socket, which is
+// in $XDG_RUNTIME_DIR if set, or in a per-user directory under the temporary
+// directory otherwise.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "abcrypt-agent.sock")
+	}
+
+	return filepath.Join(os.TempDir(), fmt.Sprintf("abcrypt-%v", os.Getuid()), "agent.sock")
+}
+
+// Listen creates a Unix domain socket at path which only the current user can
+// access. A stale socket left by an agent which has exited is removed, but an
+// error is returned if another agent is listening on it.
+//
+// The directory of path is created if it does not exist. It must be a
+// directory, not a symbolic link, owned by the current user with mode 0700
+// where file ownership is available.
+func Listen(path string) (*net.UnixListener, error) {
+	if err := checkSocketDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// checkSocketDir creates dir if it does not exist, and reports an error unless
+// only the current user can access it.
+func checkSocketDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode().Type() == fs.ModeSymlink:
+		return fmt.Errorf("agent: %v is a symbolic link", dir)
+	case !info.IsDir():
+		return fmt.Errorf("agent: %v is not a directory", dir)
+	}
+
+	return checkPrivate(dir, info)
+}
+
+// removeStaleSocket removes the socket at path if nothing is listening on it.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode().Type() != fs.ModeSocket {
+		// Let the listener report the error.
+		return nil
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+
+		return fmt.Errorf("agent: another agent is listening on %v", path)
+	}
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return nil
+	}
+
+	return os.Remove(path)
+}
+
+func (a *Agent) serveConn(conn *net.UnixConn) {
+	defer conn.Close()
+
+	if err := checkPeer(conn); err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, maxRequestSize)
+
+	enc := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp := response{Code: codeBadRequest, Error: err.Error()}
+			_ = enc.Encode(&resp)
+
+			return
+		}
+
+		resp := a.handle(&req)
+		clear(req.Passphrase)
+
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (a *Agent) handle(req *request) *response {
+	var (
+		data []byte
+		err  error
+	)
+
+	ctx := context.Background()
+
+	switch req.Op {
+	case opAdd:
+		err = a.Add(req.Passphrase, time.Duration(req.TTL)*time.Second)
+	case opLock:
+		a.Lock()
+	case opDerive:
+		params := abcrypt.Params{MemoryCost: req.MemoryCost, TimeCost: req.TimeCost, Parallelism: req.Parallelism}
+		if err = params.Validate(); err != nil {
+			break
+		}
+
+		data, err = abcrypt.DeriveKeyWithOptions(ctx, nil, req.Salt, req.Argon2Type, params, a.options()...)
+	case opEncrypt:
+		params := abcrypt.Params{MemoryCost: req.MemoryCost, TimeCost: req.TimeCost, Parallelism: req.Parallelism}
+		if err = params.Validate(); err != nil {
+			break
+		}
+
+		opts := append(a.options(), abcrypt.WithArgon2Type(req.Argon2Type), abcrypt.WithParams(params.MemoryCost, params.TimeCost, uint8(params.Parallelism)))
+		data, err = abcrypt.EncryptWithOptions(ctx, req.Data, nil, opts...)
+	case opDecrypt:
+		data, err = abcrypt.DecryptWithOptions(ctx, req.Data, nil, a.options()...)
+	case opVerify:
+		_, err = abcrypt.DecryptWithOptions(ctx, req.Data, nil, a.options()...)
+	default:
+		return &response{Code: codeBadRequest, Error: fmt.Sprintf("unknown operation `%v`", req.Op)}
+	}
+
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return &response{Data: data}
+}
+
+func (a *Agent) options() []abcrypt.Option {
+	return append([]abcrypt.Option{abcrypt.WithKeyDeriver(a)}, a.opts...)
+}
+
+// DeriveKey derives the key from the passphrase held by the agent, ignoring
+// the given passphrase. Keys for the same salt, Argon2 type and Argon2
+// parameters are cached until the TTL expires.
+func (a *Agent) DeriveKey(ctx context.Context, _, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+	return a.deriveKey(ctx, salt, argon2Type, params)
+}
+
+func (a *Agent) deriveKey(ctx context.Context, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+	if len(salt) != saltSize {
+		return nil, errors.New("agent: invalid salt length")
+	}
+
+	ck := cacheKey{[saltSize]byte(salt), argon2Type, params.MemoryCost, params.TimeCost, params.Parallelism}
+
+	a.mu.Lock()
+
+	if buf, ok := a.keys[ck]; ok {
+		k := append([]byte(nil), buf.b...)
+		a.mu.Unlock()
+
+		return k, nil
+	}
+
+	if a.passphrase == nil {
+		a.mu.Unlock()
+
+		return nil, ErrLocked
+	}
+
+	// Copy the passphrase so that Argon2 runs without holding the lock.
+	passphrase, err := newLockedBuffer(len(a.passphrase.b))
+	if err != nil {
+		a.mu.Unlock()
+
+		return nil, err
+	}
+	defer passphrase.destroy()
+
+	copy(passphrase.b, a.passphrase.b)
+	a.mu.Unlock()
+
+	k, err := abcrypt.Argon2KeyDeriver{}.DeriveKey(ctx, passphrase.b, salt, argon2Type, params)
+	if err != nil {
+		return nil, err
+	}
+
+	a.cacheKey(ck, k)
+
+	return k, nil
+}
+
+func (a *Agent) cacheKey(ck cacheKey, k []byte) {
+	buf, err := newLockedBuffer(len(k))
+	if err != nil {
+		// The key is still returned, just not cached.
+		return
+	}
+
+	copy(buf.b, k)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.passphrase == nil {
+		// Locked while deriving.
+		buf.destroy()
+
+		return
+	}
+
+	if old, ok := a.keys[ck]; ok {
+		old.destroy()
+	} else {
+		a.order = append(a.order, ck)
+	}
+
+	a.keys[ck] = buf
+
+	if len(a.order) > maxCachedKeys {
+		oldest := a.order[0]
+		a.order = a.order[1:]
+		a.keys[oldest].destroy()
+		delete(a.keys, oldest)
+	}
+}
@@ -0,0 +1,444 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/agent"
+)
+
+const passphrase = "passphrase"
+
+func newAgent(t *testing.T, opts ...abcrypt.Option) (*agent.Agent, *agent.Client) {
+	t.Helper()
+
+	// Keep the socket path short, as it is limited to about 100 bytes.
+	dir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "agent.sock")
+
+	l, err := agent.Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := agent.New(time.Minute, opts...)
+
+	go func() {
+		_ = a.Serve(l)
+	}()
+	t.Cleanup(func() {
+		a.Lock()
+		l.Close()
+	})
+
+	return a, agent.NewClient(socket)
+}
+
+func TestAgentDecrypt(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t)
+
+	dataEnc, err := os.ReadFile("../testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile("../testdata/data.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Decrypt(context.Background(), dataEnc); !errors.Is(err, agent.ErrLocked) {
+		t.Fatalf("expected `%v`, got `%v`", agent.ErrLocked, err)
+	}
+
+	if err := client.Add(context.Background(), []byte(passphrase), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := client.Decrypt(context.Background(), dataEnc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(plaintext, data) {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+
+	if err := client.Verify(context.Background(), dataEnc); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Lock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Verify(context.Background(), dataEnc); !errors.Is(err, agent.ErrLocked) {
+		t.Fatalf("expected `%v`, got `%v`", agent.ErrLocked, err)
+	}
+}
+
+func TestAgentIncorrectPassphrase(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t)
+
+	dataEnc, err := os.ReadFile("../testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Add(context.Background(), []byte("password"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Decrypt(context.Background(), dataEnc)
+
+	var invalidHeaderMACError *abcrypt.InvalidHeaderMACError
+	if !errors.As(err, &invalidHeaderMACError) {
+		t.Fatalf("unexpected error type: %v", err)
+	}
+
+	if mac := invalidHeaderMACError.MAC[:]; !slices.Equal(mac, dataEnc[84:148]) {
+		t.Errorf("expected invalid header MAC `%v`, got `%v`", dataEnc[84:148], mac)
+	}
+}
+
+func TestAgentEncrypt(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t)
+
+	if err := client.Add(context.Background(), []byte(passphrase), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	params := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+
+	ciphertext, err := client.Encrypt(context.Background(), []byte("Hello, world!\n"), abcrypt.Argon2id, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := abcrypt.Decrypt(ciphertext, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != "Hello, world!\n" {
+		t.Error("unexpected mismatch between plaintext and input data")
+	}
+}
+
+func TestClientKeyDeriver(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t)
+
+	if err := client.Add(context.Background(), []byte(passphrase), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte("Hello, world!\n"), nil, abcrypt.WithParams(32, 3, 4), abcrypt.WithKeyDeriver(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, nil, abcrypt.WithKeyDeriver(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != "Hello, world!\n" {
+		t.Error("unexpected mismatch between plaintext and input data")
+	}
+}
+
+func TestAgentTTL(t *testing.T) {
+	t.Parallel()
+
+	a, client := newAgent(t)
+
+	if err := a.Add([]byte(passphrase), 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	salt := make([]byte, 32)
+	params := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+
+	if _, err := client.DeriveKey(context.Background(), nil, salt, abcrypt.Argon2id, params); !errors.Is(err, agent.ErrLocked) {
+		t.Fatalf("expected `%v`, got `%v`", agent.ErrLocked, err)
+	}
+}
+
+func TestAgentUnsupportedInput(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t)
+
+	if err := client.Add(context.Background(), []byte(passphrase), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	salt := make([]byte, 32)
+	params := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+
+	argon2d, err := abcrypt.ParseArgon2Type("argon2d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.DeriveKey(context.Background(), nil, salt, argon2d, params)
+
+	var unsupportedKDFErr *abcrypt.UnsupportedKDFError
+	if !errors.As(err, &unsupportedKDFErr) {
+		t.Fatalf("expected error type `%T`, got `%T`", unsupportedKDFErr, err)
+	}
+
+	// An invalid Argon2 type cannot be sent.
+	var argon2TypeErr *abcrypt.InvalidArgon2TypeError
+	if _, err := client.DeriveKey(context.Background(), nil, salt, abcrypt.Argon2Type(3), params); !errors.As(err, &argon2TypeErr) {
+		t.Errorf("expected error type `%T`, got `%T`", argon2TypeErr, err)
+	}
+
+	// The agent keeps running after rejecting the request.
+	if _, err := client.DeriveKey(context.Background(), nil, salt, abcrypt.Argon2id, params); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAgentEncryptInvalidParams(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t)
+
+	if err := client.Add(context.Background(), []byte(passphrase), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The degree of parallelism must not be truncated to 4.
+	params := abcrypt.Params{MemoryCost: 32 * 260, TimeCost: 3, Parallelism: 260}
+
+	_, err := client.Encrypt(context.Background(), []byte("Hello, world!\n"), abcrypt.Argon2id, params)
+
+	var remoteError *agent.RemoteError
+	if !errors.As(err, &remoteError) || remoteError.Code != "bad-request" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAgentDeriveInvalidParams(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t)
+
+	if err := client.Add(context.Background(), []byte(passphrase), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	salt := make([]byte, 32)
+
+	for _, params := range []abcrypt.Params{
+		{MemoryCost: 32, TimeCost: 0, Parallelism: 4},
+		{MemoryCost: 32, TimeCost: 3, Parallelism: 0},
+		{MemoryCost: 7, TimeCost: 3, Parallelism: 1},
+	} {
+		_, err := client.DeriveKey(context.Background(), nil, salt, abcrypt.Argon2id, params)
+
+		var remoteError *agent.RemoteError
+		if !errors.As(err, &remoteError) || remoteError.Code != "bad-request" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestAgentDeriveMemoryBudget(t *testing.T) {
+	t.Parallel()
+
+	_, client := newAgent(t, abcrypt.WithMemoryBudgetNoWait(abcrypt.NewMemoryBudget(16)))
+
+	if err := client.Add(context.Background(), []byte(passphrase), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	salt := make([]byte, 32)
+
+	if _, err := client.DeriveKey(context.Background(), nil, salt, abcrypt.Argon2id, abcrypt.Params{MemoryCost: 16, TimeCost: 3, Parallelism: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := client.DeriveKey(context.Background(), nil, salt, abcrypt.Argon2id, abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4})
+
+	var remoteError *agent.RemoteError
+	if !errors.As(err, &remoteError) || !strings.Contains(remoteError.Message, "memory budget exceeded") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestListenStaleSocket(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "agent.sock")
+
+	// Leave the socket behind as an agent which has exited does.
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stale.SetUnlinkOnClose(false)
+
+	if err := stale.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := agent.Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// The socket in use is not removed.
+	if _, err := agent.Listen(socket); err == nil {
+		t.Error("expected error for the socket in use")
+	}
+}
+
+func TestListenInsecureDir(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions are not available")
+	}
+
+	dir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	shared := filepath.Join(dir, "shared")
+	if err := os.Mkdir(shared, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chmod(shared, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := agent.Listen(filepath.Join(shared, "agent.sock")); err == nil {
+		t.Error("expected error for the directory which other users can access")
+	}
+
+	private := filepath.Join(dir, "private")
+	if err := os.Mkdir(private, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(private, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := agent.Listen(filepath.Join(link, "agent.sock")); err == nil {
+		t.Error("expected error for the symbolic link")
+	}
+
+	l, err := agent.Listen(filepath.Join(dir, "new", "agent.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	info, err := os.Stat(filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("expected mode `%v`, got `%v`", fs.FileMode(0o700), perm)
+	}
+}
+
+func TestAgentInvalidArgon2Type(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "agent.sock")
+
+	l, err := agent.Listen(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		_ = agent.New(time.Minute).Serve(l)
+	}()
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(`{"op":"derive","argon2Type":"argon2x"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Code != "bad-request" {
+		t.Errorf("expected code `%v`, got `%v`", "bad-request", resp.Code)
+	}
+}
+
+func TestPeerError(t *testing.T) {
+	t.Parallel()
+
+	err := &agent.PeerError{UID: 1000}
+	expected := "agent: rejected connection with uid 1000"
+
+	if err.Error() != expected {
+		t.Errorf("expected `%v`, got `%v`", expected, err.Error())
+	}
+}
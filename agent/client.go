@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// ErrNoSocket represents an error due to the agent socket was not specified.
+var ErrNoSocket = errors.New("agent: " + EnvSocket + " is not set")
+
+// Client represents a client of an [Agent].
+//
+// The client refuses to send requests to an agent which runs as a different
+// user where peer credentials are available.
+type Client struct {
+	path string
+}
+
+// NewClient creates a new [Client] which connects to the agent socket at
+// path.
+func NewClient(path string) *Client {
+	c := Client{path}
+
+	return &c
+}
+
+// NewClientFromEnv creates a new [Client] which connects to the agent socket
+// specified by the [EnvSocket] environment variable.
+func NewClientFromEnv() (*Client, error) {
+	path := os.Getenv(EnvSocket)
+	if path == "" {
+		return nil, ErrNoSocket
+	}
+
+	return NewClient(path), nil
+}
+
+// Add makes the agent hold the passphrase for ttl. If ttl is zero, the
+// default TTL of the agent is used.
+func (c *Client) Add(ctx context.Context, passphrase []byte, ttl time.Duration) error {
+	req := request{Op: opAdd, Passphrase: passphrase, TTL: int64(ttl / time.Second)}
+	_, err := c.call(ctx, &req)
+
+	return err
+}
+
+// Lock makes the agent wipe the passphrase and the derived keys.
+func (c *Client) Lock(ctx context.Context) error {
+	req := request{Op: opLock}
+	_, err := c.call(ctx, &req)
+
+	return err
+}
+
+// Encrypt makes the agent encrypt the plaintext with the given Argon2 type and
+// Argon2 parameters.
+func (c *Client) Encrypt(ctx context.Context, plaintext []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+	req := request{
+		Op:          opEncrypt,
+		Data:        plaintext,
+		Argon2Type:  argon2Type,
+		MemoryCost:  params.MemoryCost,
+		TimeCost:    params.TimeCost,
+		Parallelism: params.Parallelism,
+	}
+
+	return c.call(ctx, &req)
+}
+
+// Decrypt makes the agent decrypt the ciphertext.
+func (c *Client) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if _, err := abcrypt.NewParams(ciphertext); err != nil {
+		return nil, err
+	}
+
+	req := request{Op: opDecrypt, Data: ciphertext}
+
+	return c.call(ctx, &req)
+}
+
+// Verify makes the agent check that the ciphertext decrypts successfully.
+func (c *Client) Verify(ctx context.Context, ciphertext []byte) error {
+	if _, err := abcrypt.NewParams(ciphertext); err != nil {
+		return err
+	}
+
+	req := request{Op: opVerify, Data: ciphertext}
+	_, err := c.call(ctx, &req)
+
+	return err
+}
+
+// DeriveKey makes the agent derive the key from the passphrase it holds,
+// ignoring the given passphrase.
+//
+// This implements [abcrypt.KeyDeriver].
+func (c *Client) DeriveKey(ctx context.Context, _, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+	req := request{
+		Op:          opDerive,
+		Salt:        salt,
+		Argon2Type:  argon2Type,
+		MemoryCost:  params.MemoryCost,
+		TimeCost:    params.TimeCost,
+		Parallelism: params.Parallelism,
+	}
+
+	return c.call(ctx, &req)
+}
+
+func (c *Client) call(ctx context.Context, req *request) ([]byte, error) {
+	// An invalid Argon2 type is rejected before connecting to the agent.
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "unix", c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Do not send the passphrase or the data to an agent of another user.
+	if err := checkPeer(conn.(*net.UnixConn)); err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := conn.Write(append(b, '\n')); err != nil {
+		return nil, ctxOr(ctx, err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, maxRequestSize)
+
+	if !scanner.Scan() {
+		err := scanner.Err()
+		if err == nil {
+			err = errors.New("agent: connection closed")
+		}
+
+		return nil, ctxOr(ctx, err)
+	}
+
+	var resp response
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		return nil, err
+	}
+
+	if err := resp.toError(req.Data); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+func ctxOr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package agent
+
+import "syscall"
+
+// madvDontDump is MADV_DONTDUMP, which excludes the pages from core dumps.
+const madvDontDump = 0x10
+
+// newLockedBuffer returns a buffer of n bytes which is locked into memory, so
+// it is never written to swap.
+func newLockedBuffer(n int) (*lockedBuffer, error) {
+	b, err := syscall.Mmap(-1, 0, n, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Mlock(b); err != nil {
+		_ = syscall.Munmap(b)
+
+		return nil, err
+	}
+
+	_ = syscall.Madvise(b, madvDontDump)
+
+	buf := lockedBuffer{b}
+
+	return &buf, nil
+}
+
+func (b *lockedBuffer) destroy() {
+	if b.b == nil {
+		return
+	}
+
+	// The buffer may have been resliced, so restore the whole mapping.
+	mem := b.b[:cap(b.b)]
+	clear(mem)
+	_ = syscall.Munlock(mem)
+	_ = syscall.Munmap(mem)
+	b.b = nil
+}
@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build !linux
+
+package agent
+
+// newLockedBuffer returns a buffer of n bytes. Memory locking is not
+// implemented on this platform, so the buffer is only wiped when destroyed.
+func newLockedBuffer(n int) (*lockedBuffer, error) {
+	buf := lockedBuffer{make([]byte, n)}
+
+	return &buf, nil
+}
+
+func (b *lockedBuffer) destroy() {
+	clear(b.b[:cap(b.b)])
+	b.b = nil
+}
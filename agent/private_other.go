@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build !unix
+
+package agent
+
+import "io/fs"
+
+// checkPrivate relies on the access control of the directory, because the
+// owner and the Unix permissions of a file are not available on this platform.
+func checkPrivate(_ string, _ fs.FileInfo) error {
+	return nil
+}
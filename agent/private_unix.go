@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build unix
+
+package agent
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// checkPrivate reports an error unless the file is owned by the current user
+// and has mode 0700.
+func checkPrivate(name string, info fs.FileInfo) error {
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("agent: %v has mode %#o, expected 0700", name, uint32(perm))
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("agent: cannot get the owner of %v", name)
+	}
+
+	if int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("agent: %v is owned by uid %v", name, stat.Uid)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package agent
+
+import (
+	"errors"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// The protocol is a sequence of JSON objects, one per line. The client sends
+// a request and the agent replies with exactly one response. The Argon2 type
+// is sent as its name, such as "argon2id", and an unknown name is rejected
+// when the request is decoded.
+
+const (
+	opAdd     = "add"
+	opLock    = "lock"
+	opDerive  = "derive"
+	opEncrypt = "encrypt"
+	opDecrypt = "decrypt"
+	opVerify  = "verify"
+)
+
+const (
+	codeLocked           = "locked"
+	codeInvalidHeaderMAC = "invalid-header-mac"
+	codeInvalidMAC       = "invalid-mac"
+	codeUnsupported      = "unsupported"
+	codeBadRequest       = "bad-request"
+	codeInternal         = "internal"
+)
+
+type request struct {
+	Op          string             `json:"op"`
+	Passphrase  []byte             `json:"passphrase,omitempty"`
+	TTL         int64              `json:"ttl,omitempty"`
+	Data        []byte             `json:"data,omitempty"`
+	Salt        []byte             `json:"salt,omitempty"`
+	Argon2Type  abcrypt.Argon2Type `json:"argon2Type"`
+	MemoryCost  uint32             `json:"memoryCost,omitempty"`
+	TimeCost    uint32             `json:"timeCost,omitempty"`
+	Parallelism uint32             `json:"parallelism,omitempty"`
+}
+
+type response struct {
+	Data  []byte `json:"data,omitempty"`
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ErrLocked represents an error due to the agent did not hold a passphrase.
+var ErrLocked = errors.New("agent: no passphrase is held")
+
+// RemoteError represents an error which occurred in the agent.
+type RemoteError struct {
+	// Code represents the error code sent by the agent.
+	Code string
+
+	// Message represents the error message sent by the agent.
+	Message string
+}
+
+// Error returns a string representation of a [RemoteError].
+func (e *RemoteError) Error() string {
+	return "agent: " + e.Message
+}
+
+func errorResponse(err error) *response {
+	var (
+		invalidHeaderMACError *abcrypt.InvalidHeaderMACError
+		invalidMACError       *abcrypt.InvalidMACError
+		unsupportedKDFError   *abcrypt.UnsupportedKDFError
+		invalidParamsError    *abcrypt.InvalidParamsError
+	)
+
+	code := codeInternal
+
+	switch {
+	case errors.Is(err, ErrLocked):
+		code = codeLocked
+	case errors.As(err, &invalidHeaderMACError):
+		code = codeInvalidHeaderMAC
+	case errors.As(err, &invalidMACError):
+		code = codeInvalidMAC
+	case errors.As(err, &unsupportedKDFError):
+		return &response{Code: codeUnsupported, Error: unsupportedKDFError.Reason}
+	case errors.As(err, &invalidParamsError), errors.Is(err, abcrypt.ErrInvalidSaltLength):
+		code = codeBadRequest
+	}
+
+	resp := response{Code: code, Error: err.Error()}
+
+	return &resp
+}
+
+// toError converts the error in the response to the corresponding error
+// type. data is the input of the request, which is used to restore the header
+// MAC.
+func (r *response) toError(data []byte) error {
+	switch r.Code {
+	case "":
+		return nil
+	case codeLocked:
+		return ErrLocked
+	case codeInvalidHeaderMAC:
+		var mac [64]byte
+		if len(data) >= abcrypt.HeaderSize {
+			copy(mac[:], data[84:abcrypt.HeaderSize])
+		}
+
+		return &abcrypt.InvalidHeaderMACError{MAC: mac}
+	case codeInvalidMAC:
+		return &abcrypt.InvalidMACError{Err: &RemoteError{r.Code, r.Error}}
+	case codeUnsupported:
+		return &abcrypt.UnsupportedKDFError{Reason: r.Error}
+	default:
+		return &RemoteError{r.Code, r.Error}
+	}
+}
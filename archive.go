@@ -0,0 +1,434 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ArchiveWriter writes a tar archive of files encrypted in the abcrypt
+// encrypted data format.
+//
+// The whole archive is a single encrypted payload, so neither the names, the
+// sizes nor the structure of the files are visible without the passphrase.
+// The archive is streamed through a [Writer], so the memory usage does not
+// depend on the size of the files.
+type ArchiveWriter struct {
+	w  *Writer
+	tw *tar.Writer
+}
+
+// NewArchiveWriter creates a new [ArchiveWriter] which writes the encrypted
+// archive to w.
+//
+// The options are the same as [NewEncryptorWithOptions].
+// [ArchiveWriter.Close] must be called to finish the archive and to write the
+// MAC of the ciphertext.
+func NewArchiveWriter(ctx context.Context, w io.Writer, passphrase []byte, opts ...Option) (*ArchiveWriter, error) {
+	sw, err := NewWriter(ctx, w, passphrase, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	aw := ArchiveWriter{sw, tar.NewWriter(sw)}
+
+	return &aw, nil
+}
+
+// WriteHeader writes hdr and prepares to accept the contents of the file. See
+// [tar.Writer.WriteHeader].
+func (w *ArchiveWriter) WriteHeader(hdr *tar.Header) error {
+	return w.tw.WriteHeader(hdr)
+}
+
+// Write writes to the current file in the archive. See [tar.Writer.Write].
+func (w *ArchiveWriter) Write(b []byte) (int, error) {
+	return w.tw.Write(b)
+}
+
+// AddDir adds the directory tree dir to the archive. The names of the entries
+// are the slash-separated paths relative to dir, and dir itself is not added.
+//
+// The directories, the regular files and the symbolic links are added with
+// their modes and modification times. Any other type of file results in an
+// error wrapping [ErrUnsupportedFileType].
+func (w *ArchiveWriter) AddDir(dir string) error {
+	return addDir(w.tw, dir)
+}
+
+// Close finishes the archive, and writes the MAC of the ciphertext. It does
+// not close the underlying writer.
+func (w *ArchiveWriter) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+
+	return w.w.Close()
+}
+
+// PackFile writes the directory tree dir to the named file as an encrypted
+// archive, which [ArchiveReader] reads.
+//
+// The file is written atomically as [EncryptFile], and the options are the
+// same.
+func PackFile(ctx context.Context, name, dir string, passphrase []byte, opts ...Option) error {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		tw := tar.NewWriter(pw)
+
+		err := addDir(tw, dir)
+		if err == nil {
+			err = tw.Close()
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	err := EncryptFile(ctx, name, pr, passphrase, opts...)
+
+	// Stop the writer if the encryption failed before reading everything.
+	pr.CloseWithError(errors.New("abcrypt: encryption stopped"))
+	<-done
+
+	return err
+}
+
+func addDir(tw *tar.Writer, dir string) error {
+	return filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+
+		switch {
+		case info.Mode().IsRegular(), info.IsDir():
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(name); err != nil {
+				return err
+			}
+		default:
+			return &fs.PathError{Op: "archive", Path: name, Err: ErrUnsupportedFileType}
+		}
+
+		hdr, err := tar.FileInfoHeader(info, filepath.ToSlash(link))
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+
+		return err
+	})
+}
+
+// ArchiveReader reads a tar archive encrypted in the abcrypt encrypted data
+// format, which is written by [ArchiveWriter].
+//
+// Since the MAC of the ciphertext is at the end of the data, the entries are
+// not authenticated until [ArchiveReader.Next] returns [io.EOF]. If the MAC is
+// invalid, it returns an [InvalidMACError] instead, and the entries already
+// read must be discarded.
+type ArchiveReader struct {
+	r  *Reader
+	tr *tar.Reader
+}
+
+// NewArchiveReader creates a new [ArchiveReader] which reads the encrypted
+// archive from r. The header is read and verified before this returns.
+//
+// The options are the same as [NewDecryptorWithOptions].
+func NewArchiveReader(ctx context.Context, r io.Reader, passphrase []byte, opts ...Option) (*ArchiveReader, error) {
+	sr, err := NewReader(ctx, r, passphrase, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ar := ArchiveReader{sr, tar.NewReader(sr)}
+
+	return &ar, nil
+}
+
+// Next advances to the next entry in the archive. See [tar.Reader.Next].
+//
+// At the end of the archive, the rest of the ciphertext is read and its MAC
+// is verified before this returns [io.EOF]. If the archive is malformed, the
+// MAC is verified as well, and an [InvalidMACError] is returned instead if it
+// is invalid.
+func (r *ArchiveReader) Next() (*tar.Header, error) {
+	hdr, err := r.tr.Next()
+	if err != nil {
+		return nil, r.fail(err)
+	}
+
+	return hdr, nil
+}
+
+// Read reads from the current entry in the archive. See [tar.Reader.Read].
+func (r *ArchiveReader) Read(b []byte) (int, error) {
+	return r.tr.Read(b)
+}
+
+// Extract extracts the remaining entries of the archive into the existing
+// directory dir, and restores their modes and modification times.
+//
+// The directories, the regular files and the symbolic links are extracted. An
+// entry whose name or link target would be outside dir, or whose parent is
+// not a directory, results in an error wrapping [tar.ErrInsecurePath], and
+// any other type of entry results in an error wrapping
+// [ErrUnsupportedFileType]. An existing file is never overwritten. All the
+// files are created and modified through an [os.Root] opened on dir, so a
+// symbolic link cannot redirect them outside dir.
+//
+// The entries are written before the MAC of the ciphertext is verified, so
+// if this returns an error, the files in dir must be discarded. Extracting
+// into a new temporary directory and renaming it on success avoids leaving
+// them.
+func (r *ArchiveReader) Extract(dir string) error {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	type dirInfo struct {
+		name    string
+		mode    fs.FileMode
+		modTime time.Time
+	}
+
+	var dirs []dirInfo
+
+	for {
+		hdr, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		name, err := localName(root, hdr)
+		if err != nil {
+			return r.fail(err)
+		}
+
+		if name == "." {
+			continue
+		}
+
+		mode := hdr.FileInfo().Mode().Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := mkdirAll(root, name); err != nil {
+				return err
+			}
+
+			dirs = append(dirs, dirInfo{name, mode, hdr.ModTime})
+		case tar.TypeReg:
+			if err := r.extractFile(root, name, mode); err != nil {
+				return err
+			}
+
+			if err := root.Chtimes(name, hdr.AccessTime, hdr.ModTime); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			target := filepath.FromSlash(hdr.Linkname)
+			if !localLink(root, name, target) {
+				return r.fail(&fs.PathError{Op: "extract", Path: hdr.Name, Err: tar.ErrInsecurePath})
+			}
+
+			if err := root.Symlink(target, name); err != nil {
+				return err
+			}
+		default:
+			return r.fail(&fs.PathError{Op: "extract", Path: hdr.Name, Err: ErrUnsupportedFileType})
+		}
+	}
+
+	// The modes are restored after the contents are extracted, since a
+	// directory may not be writable, and the modification times are restored
+	// from the deepest directory, since extracting into a directory updates
+	// it.
+	for _, d := range slices.Backward(dirs) {
+		if err := root.Chmod(d.name, d.mode); err != nil {
+			return err
+		}
+
+		if err := root.Chtimes(d.name, time.Time{}, d.modTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fail returns an [InvalidMACError] instead of err if the MAC of the
+// ciphertext is invalid, since a modified ciphertext is a likely cause of a
+// malformed entry.
+func (r *ArchiveReader) fail(err error) error {
+	if _, macErr := io.Copy(io.Discard, r.r); macErr != nil {
+		return macErr
+	}
+
+	return err
+}
+
+func (r *ArchiveReader) extractFile(root *os.Root, name string, mode fs.FileMode) error {
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r.tr); err != nil {
+		return err
+	}
+
+	if err := f.Chmod(mode); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// localName returns the name of the entry as a path relative to the root, and
+// creates its parent directories. The parent directories must not be symbolic
+// links, so a symbolic link in the archive cannot redirect the entries.
+func localName(root *os.Root, hdr *tar.Header) (string, error) {
+	name, err := filepath.Localize(cleanName(hdr.Name))
+	if err != nil {
+		return "", &fs.PathError{Op: "extract", Path: hdr.Name, Err: tar.ErrInsecurePath}
+	}
+
+	if dir := filepath.Dir(name); dir != "." {
+		if err := mkdirAll(root, dir); err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// localLink reports whether the target of the symbolic link stays within the
+// root. The elements of the target are followed from the directory of the
+// link, and the element left by each ".." must be an existing directory rather
+// than a symbolic link, so a chain of symbolic links cannot escape the root.
+// Since an existing directory is never replaced, this holds for the later
+// entries as well.
+func localLink(root *os.Root, name, target string) bool {
+	if filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return false
+	}
+
+	p := filepath.Dir(name)
+
+	for _, elem := range strings.Split(target, string(filepath.Separator)) {
+		switch elem {
+		case "", ".":
+		case "..":
+			if p == "." {
+				return false
+			}
+
+			if info, err := root.Lstat(p); err != nil || !info.IsDir() {
+				return false
+			}
+
+			p = filepath.Dir(p)
+		default:
+			p = filepath.Join(p, elem)
+		}
+	}
+
+	return true
+}
+
+// cleanName removes the trailing slash of a directory and the leading "./"
+// from the name of an entry. The other elements are kept, so a name
+// containing ".." is rejected by [filepath.Localize].
+func cleanName(name string) string {
+	for len(name) > 1 && strings.HasSuffix(name, "/") {
+		name = strings.TrimSuffix(name, "/")
+	}
+
+	for len(name) > 2 && strings.HasPrefix(name, "./") {
+		name = strings.TrimPrefix(name, "./")
+	}
+
+	return name
+}
+
+// mkdirAll creates the directory and its parents in the root, and checks that
+// all of them are directories rather than symbolic links.
+func mkdirAll(root *os.Root, name string) error {
+	var p string
+
+	for _, elem := range strings.Split(name, string(filepath.Separator)) {
+		p = filepath.Join(p, elem)
+
+		if err := root.Mkdir(p, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+
+		info, err := root.Lstat(p)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return &fs.PathError{Op: "extract", Path: p, Err: tar.ErrInsecurePath}
+		}
+	}
+
+	return nil
+}
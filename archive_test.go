@@ -0,0 +1,312 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var archiveTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// writeArchiveTree creates a directory tree to be archived.
+func writeArchiveTree(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(dir, "sub", "deep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, mode := range map[string]fs.FileMode{"a.txt": 0o644, "sub/b.sh": 0o755, "sub/deep/c.txt": 0o600} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.WriteFile(p, []byte(name), mode); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chmod(p, mode); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(p, archiveTime, archiveTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink(filepath.Join("deep", "c.txt"), filepath.Join(dir, "sub", "link")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Chtimes(filepath.Join(dir, "sub"), archiveTime, archiveTime); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+// checkArchiveTree checks the directory tree extracted from the archive of
+// the tree created by writeArchiveTree.
+func checkArchiveTree(t *testing.T, dir string) {
+	t.Helper()
+
+	for name, mode := range map[string]fs.FileMode{"a.txt": 0o644, "sub/b.sh": 0o755, "sub/deep/c.txt": 0o600} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Error(err)
+
+			continue
+		}
+
+		if string(b) != name {
+			t.Errorf("expected contents `%v`, got `%s`", name, b)
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !info.ModTime().Equal(archiveTime) {
+			t.Errorf("expected modification time of `%v` `%v`, got `%v`", name, archiveTime, info.ModTime())
+		}
+
+		checkMode(t, p, mode)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "sub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.ModTime().Equal(archiveTime) {
+		t.Errorf("expected modification time of directory `%v`, got `%v`", archiveTime, info.ModTime())
+	}
+
+	if runtime.GOOS != "windows" {
+		if target, err := os.Readlink(filepath.Join(dir, "sub", "link")); err != nil || target != filepath.Join("deep", "c.txt") {
+			t.Errorf("unexpected symbolic link `%v`: %v", target, err)
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+
+	src := writeArchiveTree(t)
+
+	var b bytes.Buffer
+
+	w, err := abcrypt.NewArchiveWriter(context.Background(), &b, []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.AddDir(src); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(b.Bytes(), []byte("sub/deep")) {
+		t.Error("expected the names of the files to be encrypted")
+	}
+
+	r, err := abcrypt.NewArchiveReader(context.Background(), &b, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := t.TempDir()
+	if err := r.Extract(dst); err != nil {
+		t.Fatal(err)
+	}
+
+	checkArchiveTree(t, dst)
+}
+
+func TestPackFile(t *testing.T) {
+	t.Parallel()
+
+	src := writeArchiveTree(t)
+	name := filepath.Join(t.TempDir(), "archive.abcrypt")
+
+	if err := abcrypt.PackFile(context.Background(), name, src, []byte(passphrase), append(streamOpts, abcrypt.WithVerify())...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := abcrypt.PackFile(context.Background(), name, src, []byte(passphrase), streamOpts...); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("expected error `%v`, got `%v`", fs.ErrExist, err)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := abcrypt.NewArchiveReader(context.Background(), f, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := t.TempDir()
+	if err := r.Extract(dst); err != nil {
+		t.Fatal(err)
+	}
+
+	checkArchiveTree(t, dst)
+}
+
+// newArchive returns an encrypted archive of the entries.
+func newArchive(t *testing.T, entries ...*tar.Header) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	w, err := abcrypt.NewArchiveWriter(context.Background(), &b, []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, hdr := range entries {
+		if hdr.Typeflag == tar.TypeReg {
+			hdr.Size = int64(len(data))
+		}
+
+		if err := w.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := io.WriteString(w, data); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+func TestArchiveReaderExtractInsecurePath(t *testing.T) {
+	t.Parallel()
+
+	for _, entries := range [][]*tar.Header{
+		{{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{{Name: "/evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{{Name: "a/../../evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../evil"}},
+		{{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/tmp"}},
+		{
+			{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "link/evil", Typeflag: tar.TypeReg, Mode: 0o644},
+		},
+		{
+			{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "b", Typeflag: tar.TypeSymlink, Linkname: "a/.."},
+			{Name: "c", Typeflag: tar.TypeSymlink, Linkname: "b/evil"},
+		},
+		{
+			{Name: "b", Typeflag: tar.TypeSymlink, Linkname: "a/.."},
+			{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "c", Typeflag: tar.TypeSymlink, Linkname: "b/evil"},
+		},
+	} {
+		if runtime.GOOS == "windows" && entries[0].Typeflag == tar.TypeSymlink {
+			continue
+		}
+
+		parent := t.TempDir()
+		dst := filepath.Join(parent, "dst")
+
+		if err := os.Mkdir(dst, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := abcrypt.NewArchiveReader(context.Background(), bytes.NewReader(newArchive(t, entries...)), []byte(passphrase))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.Extract(dst); !errors.Is(err, tar.ErrInsecurePath) {
+			t.Errorf("expected error `%v` for `%v`, got `%v`", tar.ErrInsecurePath, entries[len(entries)-1].Name, err)
+		}
+
+		if _, err := os.Lstat(filepath.Join(parent, "evil")); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected no file outside the directory, got `%v`", err)
+		}
+	}
+}
+
+func TestArchiveReaderExtractExistingFile(t *testing.T) {
+	t.Parallel()
+
+	dst := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dst, "a.txt"), []byte("existing"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := newArchive(t, &tar.Header{Name: "a.txt", Typeflag: tar.TypeReg, Mode: 0o644})
+
+	r, err := abcrypt.NewArchiveReader(context.Background(), bytes.NewReader(archive), []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Extract(dst); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("expected error `%v`, got `%v`", fs.ErrExist, err)
+	}
+
+	if b, _ := os.ReadFile(filepath.Join(dst, "a.txt")); string(b) != "existing" {
+		t.Errorf("expected contents `%v`, got `%s`", "existing", b)
+	}
+}
+
+func TestArchiveReaderWithInvalidMAC(t *testing.T) {
+	t.Parallel()
+
+	archive := newArchive(t, &tar.Header{Name: "a.txt", Typeflag: tar.TypeReg, Mode: 0o644})
+
+	// Both the first byte of the archive, which makes it malformed, and the
+	// padding at its end are covered by the MAC.
+	for _, i := range []int{abcrypt.HeaderSize, len(archive) - abcrypt.TagSize - 1} {
+		modified := bytes.Clone(archive)
+		modified[i] ^= 1
+
+		r, err := abcrypt.NewArchiveReader(context.Background(), bytes.NewReader(modified), []byte(passphrase))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.Extract(t.TempDir())
+
+		var invalidMACErr *abcrypt.InvalidMACError
+		if !errors.As(err, &invalidMACErr) {
+			t.Errorf("expected error type `%T` for offset `%v`, got `%T`", invalidMACErr, i, err)
+		}
+	}
+}
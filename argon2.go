@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// String returns the name of the Argon2 type, such as "argon2id".
+//
+// This also implements [flag.Value] together with [Argon2Type.Set].
+func (t Argon2Type) String() string {
+	switch t {
+	case argon2d:
+		return "argon2d"
+	case Argon2i:
+		return "argon2i"
+	case Argon2id:
+		return "argon2id"
+	default:
+		return fmt.Sprintf("Argon2Type(%v)", uint32(t))
+	}
+}
+
+// ParseArgon2Type parses the name of the Argon2 type, which is one of
+// "argon2d", "argon2i" and "argon2id".
+//
+// The name is case-insensitive. The numeric value of the Argon2 type is also
+// accepted.
+func ParseArgon2Type(s string) (Argon2Type, error) {
+	switch strings.ToLower(s) {
+	case "argon2d":
+		return argon2d, nil
+	case "argon2i":
+		return Argon2i, nil
+	case "argon2id":
+		return Argon2id, nil
+	}
+
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, &ParseArgon2TypeError{s}
+	}
+
+	switch t := Argon2Type(n); t {
+	case argon2d, Argon2i, Argon2id:
+		return t, nil
+	default:
+		return 0, &InvalidArgon2TypeError{uint32(t)}
+	}
+}
+
+// MarshalText returns the name of the Argon2 type.
+//
+// This implements [encoding.TextMarshaler].
+func (t Argon2Type) MarshalText() ([]byte, error) {
+	switch t {
+	case argon2d, Argon2i, Argon2id:
+		return []byte(t.String()), nil
+	default:
+		return nil, &InvalidArgon2TypeError{uint32(t)}
+	}
+}
+
+// UnmarshalText parses the name of the Argon2 type with [ParseArgon2Type].
+//
+// This implements [encoding.TextUnmarshaler].
+func (t *Argon2Type) UnmarshalText(text []byte) error {
+	v, err := ParseArgon2Type(string(text))
+	if err != nil {
+		return err
+	}
+
+	*t = v
+
+	return nil
+}
+
+// Set parses the name of the Argon2 type with [ParseArgon2Type].
+//
+// This implements [flag.Value] together with [Argon2Type.String].
+func (t *Argon2Type) Set(s string) error {
+	return t.UnmarshalText([]byte(s))
+}
+
+// String returns the Argon2 version in hexadecimal, such as "0x13".
+//
+// This also implements [flag.Value] together with [Argon2Version.Set].
+func (v Argon2Version) String() string {
+	return fmt.Sprintf("%#x", uint32(v))
+}
+
+// ParseArgon2Version parses the Argon2 version, which is either "0x10" or
+// "0x13".
+//
+// The decimal values "16" and "19" are also accepted.
+func ParseArgon2Version(s string) (Argon2Version, error) {
+	n, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return 0, &ParseArgon2VersionError{s}
+	}
+
+	switch v := Argon2Version(n); v {
+	case Argon2Version0x10, Argon2Version0x13:
+		return v, nil
+	default:
+		return 0, &InvalidArgon2VersionError{uint32(v)}
+	}
+}
+
+// MarshalText returns the Argon2 version in hexadecimal.
+//
+// This implements [encoding.TextMarshaler].
+func (v Argon2Version) MarshalText() ([]byte, error) {
+	switch v {
+	case Argon2Version0x10, Argon2Version0x13:
+		return []byte(v.String()), nil
+	default:
+		return nil, &InvalidArgon2VersionError{uint32(v)}
+	}
+}
+
+// UnmarshalText parses the Argon2 version with [ParseArgon2Version].
+//
+// This implements [encoding.TextUnmarshaler].
+func (v *Argon2Version) UnmarshalText(text []byte) error {
+	parsed, err := ParseArgon2Version(string(text))
+	if err != nil {
+		return err
+	}
+
+	*v = parsed
+
+	return nil
+}
+
+// Set parses the Argon2 version with [ParseArgon2Version].
+//
+// This implements [flag.Value] together with [Argon2Version.String].
+func (v *Argon2Version) Set(s string) error {
+	return v.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+func TestArgon2TypeString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		argon2Type abcrypt.Argon2Type
+		expected   string
+	}{
+		{abcrypt.Argon2d, "argon2d"},
+		{abcrypt.Argon2i, "argon2i"},
+		{abcrypt.Argon2id, "argon2id"},
+		{abcrypt.Argon2Type(3), "Argon2Type(3)"},
+	}
+
+	for _, test := range tests {
+		if s := test.argon2Type.String(); s != test.expected {
+			t.Errorf("expected `%v`, got `%v`", test.expected, s)
+		}
+	}
+}
+
+func TestParseArgon2Type(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected abcrypt.Argon2Type
+	}{
+		{"argon2d", abcrypt.Argon2d},
+		{"argon2i", abcrypt.Argon2i},
+		{"argon2id", abcrypt.Argon2id},
+		{"Argon2id", abcrypt.Argon2id},
+		{"1", abcrypt.Argon2i},
+	}
+
+	for _, test := range tests {
+		argon2Type, err := abcrypt.ParseArgon2Type(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if argon2Type != test.expected {
+			t.Errorf("expected Argon2 type `%v`, got `%v`", test.expected, argon2Type)
+		}
+	}
+
+	_, err := abcrypt.ParseArgon2Type("argon2")
+
+	var parseArgon2TypeError *abcrypt.ParseArgon2TypeError
+	if !errors.As(err, &parseArgon2TypeError) {
+		t.Error("unexpected error type")
+	}
+
+	_, err = abcrypt.ParseArgon2Type("3")
+
+	var invalidArgon2TypeError *abcrypt.InvalidArgon2TypeError
+	if !errors.As(err, &invalidArgon2TypeError) {
+		t.Error("unexpected error type")
+	}
+}
+
+func TestArgon2TypeJSON(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(abcrypt.Argon2i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `"argon2i"` {
+		t.Errorf("expected JSON `%v`, got `%s`", `"argon2i"`, b)
+	}
+
+	var argon2Type abcrypt.Argon2Type
+	if err := json.Unmarshal([]byte(`"argon2id"`), &argon2Type); err != nil {
+		t.Fatal(err)
+	}
+
+	if argon2Type != abcrypt.Argon2id {
+		t.Errorf("expected Argon2 type `%v`, got `%v`", abcrypt.Argon2id, argon2Type)
+	}
+
+	if _, err := json.Marshal(abcrypt.Argon2Type(3)); err == nil {
+		t.Error("unexpected success")
+	}
+}
+
+func TestArgon2TypeFlag(t *testing.T) {
+	t.Parallel()
+
+	argon2Type := abcrypt.Argon2id
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&argon2Type, "argon2-type", "Set the Argon2 type")
+
+	if err := fs.Parse([]string{"-argon2-type", "argon2i"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if argon2Type != abcrypt.Argon2i {
+		t.Errorf("expected Argon2 type `%v`, got `%v`", abcrypt.Argon2i, argon2Type)
+	}
+}
+
+func TestArgon2VersionString(t *testing.T) {
+	t.Parallel()
+
+	if s := abcrypt.Argon2Version0x10.String(); s != "0x10" {
+		t.Errorf("expected `%v`, got `%v`", "0x10", s)
+	}
+
+	if s := abcrypt.Argon2Version0x13.String(); s != "0x13" {
+		t.Errorf("expected `%v`, got `%v`", "0x13", s)
+	}
+}
+
+func TestParseArgon2Version(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected abcrypt.Argon2Version
+	}{
+		{"0x10", abcrypt.Argon2Version0x10},
+		{"0x13", abcrypt.Argon2Version0x13},
+		{"19", abcrypt.Argon2Version0x13},
+	}
+
+	for _, test := range tests {
+		v, err := abcrypt.ParseArgon2Version(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != test.expected {
+			t.Errorf("expected Argon2 version `%v`, got `%v`", test.expected, v)
+		}
+	}
+
+	_, err := abcrypt.ParseArgon2Version("v19")
+
+	var parseArgon2VersionError *abcrypt.ParseArgon2VersionError
+	if !errors.As(err, &parseArgon2VersionError) {
+		t.Error("unexpected error type")
+	}
+
+	_, err = abcrypt.ParseArgon2Version("0x12")
+
+	var invalidArgon2VersionError *abcrypt.InvalidArgon2VersionError
+	if !errors.As(err, &invalidArgon2VersionError) {
+		t.Error("unexpected error type")
+	}
+}
+
+func TestArgon2VersionText(t *testing.T) {
+	t.Parallel()
+
+	text, err := abcrypt.Argon2Version0x13.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v abcrypt.Argon2Version
+	if err := v.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+
+	if v != abcrypt.Argon2Version0x13 {
+		t.Errorf("expected Argon2 version `%v`, got `%v`", abcrypt.Argon2Version0x13, v)
+	}
+}
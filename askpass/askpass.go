@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package askpass reads a passphrase with a helper program, such as an
+// SSH_ASKPASS-style program or a GnuPG pinentry.
+package askpass
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Environment variables which specify the helper program.
+const (
+	// EnvAskpass specifies an askpass program.
+	EnvAskpass = "ABCRYPT_ASKPASS"
+
+	// EnvPinentry specifies a pinentry program.
+	EnvPinentry = "ABCRYPT_PINENTRY"
+
+	// EnvSSHAskpass specifies the askpass program of OpenSSH, which is used
+	// as a fallback.
+	EnvSSHAskpass = "SSH_ASKPASS"
+)
+
+// ErrCanceled represents an error due to the user canceled the prompt.
+var ErrCanceled = errors.New("askpass: canceled by the user")
+
+// Asker is the interface that wraps the Ask method.
+//
+// Ask shows prompt to the user and returns the passphrase entered.
+type Asker interface {
+	Ask(ctx context.Context, prompt string) ([]byte, error)
+}
+
+// Program is an [Asker] which runs an askpass program like SSH_ASKPASS.
+//
+// The program is run with the prompt as its only argument, and is expected to
+// write the passphrase followed by a newline to the standard output. An exit
+// status of 1 means that the user canceled the prompt.
+type Program struct {
+	// Path represents the path of the program.
+	Path string
+}
+
+// Ask runs the askpass program.
+func (p *Program) Ask(ctx context.Context, prompt string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.Path, prompt)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		defer clear(stdout.Bytes())
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, ErrCanceled
+		}
+
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("askpass: %w: %v", err, msg)
+		}
+
+		return nil, fmt.Errorf("askpass: %w", err)
+	}
+
+	b := stdout.Bytes()
+	passphrase := bytes.TrimSuffix(bytes.TrimSuffix(b, []byte("\n")), []byte("\r"))
+	defer clear(b)
+
+	return bytes.Clone(passphrase), nil
+}
+
+// FromEnv returns the [Asker] specified by the environment variables, in
+// order of [EnvAskpass], [EnvPinentry] and [EnvSSHAskpass]. It returns false
+// if none of them is set.
+//
+// If fallback is false, [EnvSSHAskpass] is not considered, so that a
+// terminal prompt is preferred over the askpass program of OpenSSH.
+func FromEnv(fallback bool) (Asker, bool) {
+	if path := os.Getenv(EnvAskpass); path != "" {
+		return &Program{path}, true
+	}
+
+	if path := os.Getenv(EnvPinentry); path != "" {
+		return &Pinentry{Path: path}, true
+	}
+
+	if path := os.Getenv(EnvSSHAskpass); path != "" && fallback {
+		return &Program{path}, true
+	}
+
+	return nil, false
+}
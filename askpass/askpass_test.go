@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build unix
+
+package askpass_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go/askpass"
+)
+
+// writeScript writes a shell script to a temporary directory and returns its
+// path.
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "helper")
+	if err := os.WriteFile(name, []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestProgram(t *testing.T) {
+	t.Parallel()
+
+	p := askpass.Program{Path: writeScript(t, `test "$1" = "Enter passphrase: " || exit 2
+printf 'passphrase\n'
+`)}
+
+	passphrase, err := p.Ask(context.Background(), "Enter passphrase: ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(passphrase) != "passphrase" {
+		t.Errorf("expected passphrase `%v`, got `%s`", "passphrase", passphrase)
+	}
+}
+
+func TestProgramCanceled(t *testing.T) {
+	t.Parallel()
+
+	p := askpass.Program{Path: writeScript(t, "exit 1\n")}
+
+	if _, err := p.Ask(context.Background(), "Enter passphrase: "); !errors.Is(err, askpass.ErrCanceled) {
+		t.Errorf("expected error `%v`, got `%v`", askpass.ErrCanceled, err)
+	}
+}
+
+func TestProgramFailed(t *testing.T) {
+	t.Parallel()
+
+	p := askpass.Program{Path: writeScript(t, "echo 'cannot open display' >&2\nexit 2\n")}
+
+	_, err := p.Ask(context.Background(), "Enter passphrase: ")
+	if err == nil || errors.Is(err, askpass.ErrCanceled) {
+		t.Fatalf("expected error other than `%v`, got `%v`", askpass.ErrCanceled, err)
+	}
+
+	if s := err.Error(); s != "askpass: exit status 2: cannot open display" {
+		t.Errorf("expected error message `%v`, got `%v`", "askpass: exit status 2: cannot open display", s)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(askpass.EnvAskpass, "")
+	t.Setenv(askpass.EnvPinentry, "")
+	t.Setenv(askpass.EnvSSHAskpass, "/usr/bin/ssh-askpass")
+
+	if _, ok := askpass.FromEnv(false); ok {
+		t.Error("expected no asker without fallback")
+	}
+
+	if asker, ok := askpass.FromEnv(true); !ok {
+		t.Error("expected asker with fallback")
+	} else if p, ok := asker.(*askpass.Program); !ok || p.Path != "/usr/bin/ssh-askpass" {
+		t.Errorf("unexpected asker `%#v`", asker)
+	}
+
+	t.Setenv(askpass.EnvPinentry, "/usr/bin/pinentry")
+
+	if asker, ok := askpass.FromEnv(false); !ok {
+		t.Error("expected asker")
+	} else if p, ok := asker.(*askpass.Pinentry); !ok || p.Path != "/usr/bin/pinentry" {
+		t.Errorf("unexpected asker `%#v`", asker)
+	}
+
+	t.Setenv(askpass.EnvAskpass, "/usr/bin/askpass")
+
+	if asker, ok := askpass.FromEnv(false); !ok {
+		t.Error("expected asker")
+	} else if p, ok := asker.(*askpass.Program); !ok || p.Path != "/usr/bin/askpass" {
+		t.Errorf("unexpected asker `%#v`", asker)
+	}
+}
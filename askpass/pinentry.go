@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package askpass
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// assuanCanceled is the error code of the Assuan protocol which means that the
+// operation was canceled (GPG_ERR_CANCELED in the pinentry source).
+const assuanCanceled = 83886179
+
+// maxLineSize is the maximum length of a line of the Assuan protocol.
+const maxLineSize = 1000
+
+// Pinentry is an [Asker] which talks the Assuan protocol to a pinentry
+// program of GnuPG, such as pinentry-gnome3 or pinentry-curses.
+type Pinentry struct {
+	// Path represents the path of the pinentry program.
+	Path string
+
+	// Title represents the title of the dialog. If it is empty, "abcrypt" is
+	// used.
+	Title string
+
+	// Description represents the text shown above the entry. If it is empty,
+	// the prompt given to [Pinentry.Ask] is used.
+	Description string
+}
+
+// AssuanError represents an error response of the Assuan protocol.
+type AssuanError struct {
+	// Code represents the error code.
+	Code int
+
+	// Message represents the error description.
+	Message string
+}
+
+// Error returns a string representation of an [AssuanError].
+func (e *AssuanError) Error() string {
+	return fmt.Sprintf("pinentry: %v (%v)", e.Message, e.Code)
+}
+
+// ProtocolError represents an error due to the pinentry program sent an
+// unexpected line.
+type ProtocolError struct {
+	// Line represents the unexpected line.
+	Line string
+}
+
+// Error returns a string representation of a [ProtocolError].
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("pinentry: unexpected response %q", e.Line)
+}
+
+// Ask runs the pinentry program and requests the passphrase with the GETPIN
+// command.
+//
+// If the user cancels the dialog, [ErrCanceled] is returned.
+func (p *Pinentry) Ask(ctx context.Context, prompt string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.Path)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	c := conn{bufio.NewReaderSize(stdout, maxLineSize), stdin}
+
+	passphrase, err := p.session(&c, prompt)
+
+	_ = stdin.Close()
+
+	if waitErr := cmd.Wait(); err == nil && waitErr != nil {
+		clear(passphrase)
+
+		return nil, waitErr
+	}
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		return nil, err
+	}
+
+	return passphrase, nil
+}
+
+func (p *Pinentry) session(c *conn, prompt string) ([]byte, error) {
+	if _, err := c.response(); err != nil {
+		return nil, err
+	}
+
+	// The options are hints for pinentry-curses and pinentry-tty, so the
+	// errors are ignored.
+	if tty := os.Getenv("GPG_TTY"); tty != "" {
+		_, _ = c.command("OPTION ttyname=" + escape(tty))
+	}
+
+	if term := os.Getenv("TERM"); term != "" {
+		_, _ = c.command("OPTION ttytype=" + escape(term))
+	}
+
+	title := p.Title
+	if title == "" {
+		title = "abcrypt"
+	}
+
+	desc := p.Description
+	if desc == "" {
+		desc = strings.TrimSuffix(strings.TrimSpace(prompt), ":")
+	}
+
+	for _, line := range []string{"SETTITLE " + escape(title), "SETDESC " + escape(desc), "SETPROMPT Passphrase:"} {
+		if _, err := c.command(line); err != nil {
+			return nil, err
+		}
+	}
+
+	passphrase, err := c.command("GETPIN")
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = c.command("BYE")
+
+	return passphrase, nil
+}
+
+type conn struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// command sends a command and returns the data of the response.
+func (c *conn) command(line string) ([]byte, error) {
+	if _, err := io.WriteString(c.w, line+"\n"); err != nil {
+		return nil, err
+	}
+
+	return c.response()
+}
+
+// response reads the lines until OK or ERR, and returns the decoded data
+// lines.
+func (c *conn) response() ([]byte, error) {
+	var data []byte
+
+	for {
+		line, err := c.r.ReadSlice('\n')
+		if err != nil {
+			clear(data)
+
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return nil, err
+		}
+
+		line = bytes.TrimSuffix(line, []byte("\n"))
+
+		switch {
+		case bytes.Equal(line, []byte("OK")) || bytes.HasPrefix(line, []byte("OK ")):
+			return data, nil
+		case bytes.HasPrefix(line, []byte("ERR ")):
+			clear(data)
+
+			return nil, parseError(string(line[4:]))
+		case bytes.HasPrefix(line, []byte("D ")):
+			data = append(data, unescape(line[2:])...)
+		case bytes.HasPrefix(line, []byte("S ")), bytes.HasPrefix(line, []byte("#")), len(line) == 0:
+			// Status and comment lines are ignored.
+		case bytes.HasPrefix(line, []byte("INQUIRE ")):
+			// No inquiry is supported, so cancel it.
+			if _, err := io.WriteString(c.w, "CAN\n"); err != nil {
+				return nil, err
+			}
+		default:
+			clear(data)
+
+			return nil, &ProtocolError{string(line)}
+		}
+
+		clear(line)
+	}
+}
+
+func parseError(s string) error {
+	code, msg, _ := strings.Cut(s, " ")
+
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return &ProtocolError{"ERR " + s}
+	}
+
+	if n&0xffff == assuanCanceled&0xffff {
+		return ErrCanceled
+	}
+
+	return &AssuanError{n, msg}
+}
+
+// escape percent-encodes the characters which cannot appear in a line of the
+// Assuan protocol.
+func escape(s string) string {
+	var b strings.Builder
+
+	for _, c := range []byte(s) {
+		switch c {
+		case '%', '\r', '\n', '\\':
+			fmt.Fprintf(&b, "%%%02X", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// unescape decodes the percent-encoded data.
+func unescape(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+
+	for i := 0; i < len(b); i++ {
+		if b[i] == '%' && i+2 < len(b) {
+			if v, err := strconv.ParseUint(string(b[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+
+				continue
+			}
+		}
+
+		out = append(out, b[i])
+	}
+
+	return out
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build unix
+
+package askpass_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go/askpass"
+)
+
+// fakePinentry returns the path of a script which speaks the Assuan protocol
+// like pinentry. The commands received are written to the returned log file.
+// getpin is the response to GETPIN.
+func fakePinentry(t *testing.T, getpin string) (string, string) {
+	t.Helper()
+
+	log := filepath.Join(t.TempDir(), "log")
+
+	script := `echo "OK Pleased to meet you"
+while IFS= read -r line; do
+	printf '%s\n' "$line" >> "` + log + `"
+	case "$line" in
+	GETPIN) printf '%s\n' "` + getpin + `" ;;
+	BYE) echo "OK closing connection"; exit 0 ;;
+	*) echo "OK" ;;
+	esac
+done
+`
+
+	return writeScript(t, script), log
+}
+
+func TestPinentry(t *testing.T) {
+	t.Setenv("GPG_TTY", "")
+	t.Setenv("TERM", "")
+
+	path, log := fakePinentry(t, `S PASSWORD_FROM_CACHE
+D pass%25word%0A
+OK`)
+
+	p := askpass.Pinentry{Path: path, Description: "Enter the passphrase for\n100%"}
+
+	passphrase, err := p.Ask(context.Background(), "Enter passphrase: ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(passphrase) != "pass%word\n" {
+		t.Errorf("expected passphrase `%v`, got `%s`", "pass%word\n", passphrase)
+	}
+
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"SETTITLE abcrypt",
+		"SETDESC Enter the passphrase for%0A100%25",
+		"SETPROMPT Passphrase:",
+		"GETPIN",
+		"BYE",
+		"",
+	}, "\n")
+	if s := string(b); s != expected {
+		t.Errorf("expected commands `%v`, got `%v`", expected, s)
+	}
+}
+
+func TestPinentryDescriptionFromPrompt(t *testing.T) {
+	t.Setenv("GPG_TTY", "/dev/pts/1")
+	t.Setenv("TERM", "xterm")
+
+	path, log := fakePinentry(t, "D passphrase\nOK")
+
+	p := askpass.Pinentry{Path: path, Title: "Title"}
+
+	if _, err := p.Ask(context.Background(), "Enter passphrase: "); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"OPTION ttyname=/dev/pts/1", "OPTION ttytype=xterm", "SETTITLE Title", "SETDESC Enter passphrase"} {
+		if !strings.Contains(string(b), line+"\n") {
+			t.Errorf("expected command `%v`, got `%v`", line, string(b))
+		}
+	}
+}
+
+func TestPinentryCanceled(t *testing.T) {
+	t.Parallel()
+
+	path, _ := fakePinentry(t, "ERR 83886179 Operation cancelled <Pinentry>")
+
+	p := askpass.Pinentry{Path: path}
+
+	if _, err := p.Ask(context.Background(), "Enter passphrase: "); !errors.Is(err, askpass.ErrCanceled) {
+		t.Errorf("expected error `%v`, got `%v`", askpass.ErrCanceled, err)
+	}
+}
+
+func TestPinentryError(t *testing.T) {
+	t.Parallel()
+
+	path, _ := fakePinentry(t, "ERR 83918950 Inappropriate ioctl for device <Pinentry>")
+
+	p := askpass.Pinentry{Path: path}
+
+	_, err := p.Ask(context.Background(), "Enter passphrase: ")
+
+	var assuanErr *askpass.AssuanError
+	if !errors.As(err, &assuanErr) {
+		t.Fatalf("expected error type `%T`, got `%T`", assuanErr, err)
+	}
+
+	if assuanErr.Code != 83918950 || assuanErr.Message != "Inappropriate ioctl for device <Pinentry>" {
+		t.Errorf("unexpected error `%+v`", assuanErr)
+	}
+}
+
+func TestPinentryProtocolError(t *testing.T) {
+	t.Parallel()
+
+	p := askpass.Pinentry{Path: writeScript(t, "echo 'Hello'\n")}
+
+	_, err := p.Ask(context.Background(), "Enter passphrase: ")
+
+	var protocolErr *askpass.ProtocolError
+	if !errors.As(err, &protocolErr) {
+		t.Fatalf("expected error type `%T`, got `%T`", protocolErr, err)
+	}
+
+	if protocolErr.Line != "Hello" {
+		t.Errorf("expected line `%v`, got `%v`", "Hello", protocolErr.Line)
+	}
+}
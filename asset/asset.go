@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package asset provides the runtime support for the Go source files
+// generated by abcrypt-embed.
+//
+// The generated file holds the encrypted files as string constants and
+// exposes an accessor for each file. The accessor decrypts the file on first
+// use with the passphrase supplied by a [Source].
+package asset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sorairolake/abcrypt-go"
+	"golang.org/x/term"
+)
+
+// ErrNoPassphrase represents an error due to no passphrase was supplied.
+var ErrNoPassphrase = errors.New("asset: no passphrase was supplied")
+
+// PassphraseFunc represents a function which supplies the passphrase.
+type PassphraseFunc func() ([]byte, error)
+
+// FromEnv returns a [PassphraseFunc] which reads the passphrase from the
+// environment variable key. It returns [ErrNoPassphrase] if the environment
+// variable is not set or is empty.
+func FromEnv(key string) PassphraseFunc {
+	return func() ([]byte, error) {
+		passphrase := os.Getenv(key)
+		if passphrase == "" {
+			return nil, fmt.Errorf("%w: %v is not set", ErrNoPassphrase, key)
+		}
+
+		return []byte(passphrase), nil
+	}
+}
+
+// Prompt returns a [PassphraseFunc] which writes prompt to standard error and
+// reads the passphrase from the terminal without echo. It returns
+// [ErrNoPassphrase] if standard input is not a terminal.
+func Prompt(prompt string) PassphraseFunc {
+	return func() ([]byte, error) {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return nil, fmt.Errorf("%w: standard input is not a terminal", ErrNoPassphrase)
+		}
+
+		fmt.Fprint(os.Stderr, prompt)
+		defer fmt.Fprintln(os.Stderr)
+
+		return term.ReadPassword(fd)
+	}
+}
+
+// FirstOf returns a [PassphraseFunc] which calls fns in order and returns the
+// first passphrase supplied. A function which returns [ErrNoPassphrase] is
+// skipped, and any other error is returned immediately.
+func FirstOf(fns ...PassphraseFunc) PassphraseFunc {
+	return func() ([]byte, error) {
+		for _, fn := range fns {
+			passphrase, err := fn()
+			if errors.Is(err, ErrNoPassphrase) {
+				continue
+			}
+
+			return passphrase, err
+		}
+
+		return nil, ErrNoPassphrase
+	}
+}
+
+// Source represents a source of the passphrase shared by the assets.
+//
+// The passphrase is requested at most once and is kept until it fails to
+// decrypt an asset.
+type Source struct {
+	mu         sync.Mutex
+	fn         PassphraseFunc
+	passphrase []byte
+}
+
+// NewSource creates a new [Source] which supplies the passphrase with fn.
+func NewSource(fn PassphraseFunc) *Source {
+	s := Source{fn: fn}
+
+	return &s
+}
+
+// Set replaces the function which supplies the passphrase and forgets the
+// passphrase already supplied. This is intended to be called from the main
+// function before the first use of the assets.
+func (s *Source) Set(fn PassphraseFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fn = fn
+	s.forget()
+}
+
+// SetPassphrase makes s supply the given passphrase.
+func (s *Source) SetPassphrase(passphrase []byte) {
+	passphrase = bytes.Clone(passphrase)
+
+	s.Set(func() ([]byte, error) {
+		return passphrase, nil
+	})
+}
+
+// Forget wipes the passphrase already supplied, so the next use of the
+// assets requests it again.
+func (s *Source) Forget() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forget()
+}
+
+func (s *Source) forget() {
+	clear(s.passphrase)
+	s.passphrase = nil
+}
+
+func (s *Source) decrypt(ciphertext []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.passphrase == nil {
+		if s.fn == nil {
+			return nil, ErrNoPassphrase
+		}
+
+		passphrase, err := s.fn()
+		if err != nil {
+			return nil, err
+		}
+
+		s.passphrase = bytes.Clone(passphrase)
+	}
+
+	plaintext, err := abcrypt.Decrypt(ciphertext, s.passphrase)
+	if err != nil {
+		var headerMACErr *abcrypt.InvalidHeaderMACError
+		if errors.As(err, &headerMACErr) {
+			s.forget()
+		}
+
+		return nil, err
+	}
+
+	return plaintext, nil
+}
+
+// Asset represents a file encrypted by abcrypt-embed.
+type Asset struct {
+	name       string
+	ciphertext string
+	source     *Source
+
+	mu        sync.Mutex
+	plaintext []byte
+}
+
+// New creates a new [Asset] named name, which is decrypted with the
+// passphrase supplied by source.
+func New(name, ciphertext string, source *Source) *Asset {
+	a := Asset{name: name, ciphertext: ciphertext, source: source}
+
+	return &a
+}
+
+// Name returns the name of the file.
+func (a *Asset) Name() string {
+	return a.name
+}
+
+// Bytes returns the decrypted contents of the file.
+//
+// The file is decrypted on first use and the result is kept in memory. If
+// decryption fails, the error is returned and the next call tries again. The
+// returned slice must not be modified.
+func (a *Asset) Bytes() ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.plaintext != nil {
+		return a.plaintext, nil
+	}
+
+	plaintext, err := a.source.decrypt([]byte(a.ciphertext))
+	if err != nil {
+		return nil, fmt.Errorf("asset: could not decrypt %v: %w", a.name, err)
+	}
+
+	if plaintext == nil {
+		plaintext = []byte{}
+	}
+
+	a.plaintext = plaintext
+
+	return plaintext, nil
+}
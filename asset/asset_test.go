@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package asset_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/asset"
+)
+
+const passphrase = "passphrase"
+
+func encrypt(s string) string {
+	return string(abcrypt.EncryptWithParams([]byte(s), []byte(passphrase), 32, 3, 4))
+}
+
+func TestAsset(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	source := asset.NewSource(func() ([]byte, error) {
+		calls++
+
+		return []byte(passphrase), nil
+	})
+
+	a := asset.New("config.json", encrypt(`{"name":"abcrypt"}`), source)
+	b := asset.New("empty.txt", encrypt(""), source)
+
+	if name := a.Name(); name != "config.json" {
+		t.Errorf("expected name `%v`, got `%v`", "config.json", name)
+	}
+
+	for range 2 {
+		plaintext, err := a.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s := string(plaintext); s != `{"name":"abcrypt"}` {
+			t.Errorf("expected plaintext `%v`, got `%v`", `{"name":"abcrypt"}`, s)
+		}
+	}
+
+	plaintext, err := b.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plaintext == nil || len(plaintext) != 0 {
+		t.Errorf("expected empty plaintext, got `%v`", plaintext)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected number of calls `%v`, got `%v`", 1, calls)
+	}
+}
+
+func TestAssetWithInvalidPassphrase(t *testing.T) {
+	t.Parallel()
+
+	source := asset.NewSource(func() ([]byte, error) {
+		return []byte("password"), nil
+	})
+	a := asset.New("config.json", encrypt(`{"name":"abcrypt"}`), source)
+
+	_, err := a.Bytes()
+
+	var headerMACErr *abcrypt.InvalidHeaderMACError
+	if !errors.As(err, &headerMACErr) {
+		t.Fatalf("expected error type `%T`, got `%T`", headerMACErr, err)
+	}
+
+	source.SetPassphrase([]byte(passphrase))
+
+	if _, err := a.Bytes(); err != nil {
+		t.Errorf("expected no error, got `%v`", err)
+	}
+}
+
+func TestAssetWithoutPassphrase(t *testing.T) {
+	t.Parallel()
+
+	source := asset.NewSource(asset.FirstOf(asset.FromEnv("ABCRYPT_ASSET_TEST_UNSET")))
+	a := asset.New("config.json", encrypt(`{"name":"abcrypt"}`), source)
+
+	if _, err := a.Bytes(); !errors.Is(err, asset.ErrNoPassphrase) {
+		t.Errorf("expected error `%v`, got `%v`", asset.ErrNoPassphrase, err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("ABCRYPT_ASSET_TEST", passphrase)
+
+	p, err := asset.FromEnv("ABCRYPT_ASSET_TEST")()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(p) != passphrase {
+		t.Errorf("expected passphrase `%v`, got `%v`", passphrase, string(p))
+	}
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go/agent"
+)
+
+type options struct {
+	socket       string
+	ttl          time.Duration
+	memoryBudget uint64
+	add          bool
+	lock         bool
+	version      bool
+}
+
+var opt options
+
+func init() {
+	flag.StringVar(&opt.socket, "socket", "", "Set the path of the agent socket")
+	flag.DurationVar(&opt.ttl, "ttl", agent.DefaultTTL, "Set the lifetime of the passphrase")
+	flag.Uint64Var(&opt.memoryBudget, "memory-budget", 0, "Limit the memory in KiB used by concurrent key derivations")
+	flag.BoolVar(&opt.add, "add", false, "Add a passphrase to the running agent")
+	flag.BoolVar(&opt.lock, "lock", false, "Remove the passphrase from the running agent")
+	flag.BoolVar(&opt.version, "version", false, "Print version number")
+
+	flag.Usage = func() {
+		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS]\n", os.Args[0]); err != nil {
+			log.Fatal(err)
+		}
+
+		flag.PrintDefaults()
+	}
+}
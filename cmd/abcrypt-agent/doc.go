@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Abcrypt-agent is an agent which holds a passphrase for the abcrypt encrypted
+// data format, like ssh-agent.
+//
+// Without -add or -lock, this runs the agent in the foreground and prints the
+// shell command which sets ABCRYPT_AGENT_SOCK. The encrypt and decrypt
+// examples use the agent instead of prompting when ABCRYPT_AGENT_SOCK is set.
+//
+//	abcrypt-agent &
+//	export ABCRYPT_AGENT_SOCK="$XDG_RUNTIME_DIR/abcrypt-agent.sock"
+//	abcrypt-agent -add
+//
+// Usage:
+//
+//	abcrypt-agent [OPTIONS]
+//
+// Options:
+//
+//	-socket <FILE>
+//		Set the path of the agent socket.
+//	-ttl <DURATION>
+//		Set the lifetime of the passphrase.
+//	-memory-budget <NUM>
+//		Limit the memory in KiB used by concurrent key derivations.
+//	-add
+//		Add a passphrase to the running agent.
+//	-lock
+//		Remove the passphrase from the running agent.
+//	-version
+//		Print version number.
+package main
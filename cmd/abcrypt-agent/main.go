@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/agent"
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+	"golang.org/x/term"
+)
+
+func main() {
+	flag.Parse()
+
+	if opt.version {
+		fmt.Printf("abcrypt-go %v\n", cli.Version)
+		os.Exit(0)
+	}
+
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	socket := opt.socket
+	if socket == "" {
+		socket = os.Getenv(agent.EnvSocket)
+	}
+
+	if socket == "" {
+		socket = agent.DefaultSocketPath()
+	}
+
+	switch {
+	case opt.add:
+		fmt.Fprint(os.Stderr, "Enter passphrase: ")
+
+		passphrase, err := term.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintln(os.Stderr)
+
+		if err := agent.NewClient(socket).Add(context.Background(), passphrase, opt.ttl); err != nil {
+			log.Fatal(err)
+		}
+	case opt.lock:
+		if err := agent.NewClient(socket).Lock(context.Background()); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		serve(socket)
+	}
+}
+
+func serve(socket string) {
+	var opts []abcrypt.Option
+	if opt.memoryBudget != 0 {
+		opts = append(opts, abcrypt.WithMemoryBudget(abcrypt.NewMemoryBudget(opt.memoryBudget)))
+	}
+
+	a := agent.New(opt.ttl, opts...)
+
+	l, err := agent.Listen(socket)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		a.Lock()
+		l.Close()
+	}()
+
+	fmt.Printf("%v=%v; export %v;\n", agent.EnvSocket, socket, agent.EnvSocket)
+
+	if err := a.Serve(l); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+const (
+	defaultMemoryCost  = 19456
+	defaultTimeCost    = 2
+	defaultParallelism = 1
+
+	defaultOutput = "abcrypt_assets.go"
+	defaultEnv    = "ABCRYPT_PASSPHRASE"
+)
+
+type options struct {
+	pkg         string
+	output      string
+	env         string
+	noPrompt    bool
+	argon2Type  abcrypt.Argon2Type
+	memoryCost  uint
+	timeCost    uint
+	parallelism uint
+	version     bool
+}
+
+var opt options
+
+func init() {
+	flag.StringVar(&opt.pkg, "package", os.Getenv("GOPACKAGE"), "Set the package name of the generated file")
+	flag.StringVar(&opt.output, "o", defaultOutput, "Set the path of the generated file")
+	flag.StringVar(&opt.env, "env", defaultEnv, "Set the environment variable which holds the passphrase")
+	flag.BoolVar(&opt.noPrompt, "no-prompt", false, "Do not make the generated file prompt for the passphrase")
+	opt.argon2Type = abcrypt.Argon2id
+	flag.Var(&opt.argon2Type, "argon2-type", "Set the Argon2 type (argon2i or argon2id)")
+	flag.UintVar(&opt.memoryCost, "memory-cost", defaultMemoryCost, "Set the memory size in KiB")
+	flag.UintVar(&opt.timeCost, "time-cost", defaultTimeCost, "Set the number of iterations")
+	flag.UintVar(&opt.parallelism, "parallelism", defaultParallelism, "Set the degree of parallelism")
+	flag.BoolVar(&opt.version, "version", false, "Print version number")
+
+	flag.Usage = func() {
+		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] <FILE>...\n", os.Args[0]); err != nil {
+			log.Fatal(err)
+		}
+
+		flag.PrintDefaults()
+	}
+}
+
+// params returns the Argon2 parameters, or an error if the Argon2 type or the
+// Argon2 parameters cannot be used for encryption.
+func (o *options) params() (abcrypt.Params, error) {
+	if o.argon2Type != abcrypt.Argon2i && o.argon2Type != abcrypt.Argon2id {
+		return abcrypt.Params{}, fmt.Errorf("%v is not supported for encryption", o.argon2Type)
+	}
+
+	if o.memoryCost > math.MaxUint32 || o.timeCost > math.MaxUint32 || o.parallelism > math.MaxUint32 {
+		return abcrypt.Params{}, errors.New("the Argon2 parameters are out of range")
+	}
+
+	p := abcrypt.Params{MemoryCost: uint32(o.memoryCost), TimeCost: uint32(o.timeCost), Parallelism: uint32(o.parallelism)}
+	if err := p.Validate(); err != nil {
+		return abcrypt.Params{}, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+func TestOptionsParams(t *testing.T) {
+	t.Parallel()
+
+	valid := options{argon2Type: abcrypt.Argon2id, memoryCost: 32, timeCost: 3, parallelism: 4}
+
+	p, err := valid.params()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p != (abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}) {
+		t.Errorf("unexpected parameters `%v`", p)
+	}
+
+	argon2d, err := abcrypt.ParseArgon2Type("argon2d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, o := range []options{
+		{argon2Type: argon2d, memoryCost: 32, timeCost: 3, parallelism: 4},
+		{argon2Type: abcrypt.Argon2id, memoryCost: 32, timeCost: 0, parallelism: 4},
+		{argon2Type: abcrypt.Argon2id, memoryCost: 32, timeCost: 3, parallelism: 0},
+		{argon2Type: abcrypt.Argon2id, memoryCost: 32 * 260, timeCost: 3, parallelism: 260},
+	} {
+		if _, err := o.params(); err == nil {
+			t.Errorf("expected error for `%+v`", o)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Abcrypt-embed is a code generator which encrypts files to the abcrypt
+// encrypted data format and embeds them into a Go source file.
+//
+// The generated file declares PassphraseSource, an [asset.Source], and a
+// function for each file which returns the decrypted contents. The files are
+// decrypted on first use. By default, the passphrase is read from the
+// environment variable specified by -env, and is prompted for on the terminal
+// if the environment variable is not set. Call PassphraseSource.Set from the
+// main function to supply the passphrase in another way.
+//
+// At generation time, the passphrase is read from the same environment
+// variable, or is prompted for twice on the terminal. Since a random salt and
+// nonce are used, every run produces a different file. The generated file is
+// written to a temporary file and renamed into place.
+//
+//	//go:generate go run github.com/sorairolake/abcrypt-go/cmd/abcrypt-embed -o secrets.go config.json
+//
+// The function name is derived from the file name, for example "config.json"
+// becomes ConfigJSON.
+//
+// Usage:
+//
+//	abcrypt-embed [OPTIONS] <FILE>...
+//
+// Arguments:
+//
+//	<FILE>...
+//		Files to embed.
+//
+// Options:
+//
+//	-package <NAME>
+//		Set the package name of the generated file.
+//	-o <FILE>
+//		Set the path of the generated file.
+//	-env <NAME>
+//		Set the environment variable which holds the passphrase.
+//	-no-prompt
+//		Do not make the generated file prompt for the passphrase.
+//	-argon2-type <TYPE>
+//		Set the Argon2 type (argon2i or argon2id).
+//	-memory-cost <NUM>
+//		Set the memory size in KiB.
+//	-time-cost <NUM>
+//		Set the number of iterations.
+//	-parallelism <NUM>
+//		Set the degree of parallelism.
+//	-version
+//		Print version number.
+//
+// [asset.Source]: https://pkg.go.dev/github.com/sorairolake/abcrypt-go/asset#Source
+package main
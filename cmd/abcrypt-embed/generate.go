@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// bytesPerLine is the number of bytes of the ciphertext written in a line of
+// the generated file.
+const bytesPerLine = 32
+
+// initialisms are the words which are written in upper case in the function
+// names.
+var initialisms = map[string]bool{
+	"api":  true,
+	"css":  true,
+	"csv":  true,
+	"html": true,
+	"id":   true,
+	"js":   true,
+	"json": true,
+	"pem":  true,
+	"sql":  true,
+	"tls":  true,
+	"toml": true,
+	"url":  true,
+	"xml":  true,
+	"yaml": true,
+}
+
+// reserved are the names declared by the generated file other than the
+// functions for the files.
+var reserved = map[string]bool{
+	"PassphraseSource": true,
+}
+
+var tmpl = template.Must(template.New("").Funcs(template.FuncMap{"literal": literal}).Parse(`// Code generated by abcrypt-embed. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/sorairolake/abcrypt-go/asset"
+
+// PassphraseSource supplies the passphrase for the assets in this file.
+{{- if .Prompt}}
+//
+// By default, the passphrase is read from the environment variable
+// {{.Env}}, or is prompted for on the terminal if it is not set.
+var PassphraseSource = asset.NewSource(asset.FirstOf(asset.FromEnv({{printf "%q" .Env}}), asset.Prompt("Enter passphrase: ")))
+{{- else}}
+//
+// By default, the passphrase is read from the environment variable
+// {{.Env}}.
+var PassphraseSource = asset.NewSource(asset.FromEnv({{printf "%q" .Env}}))
+{{- end}}
+{{range .Files}}
+var asset{{.Ident}} = asset.New({{printf "%q" .Name}}, {{literal .Ciphertext}}, PassphraseSource)
+
+// {{.Ident}} returns the decrypted contents of {{.Name}}.
+func {{.Ident}}() ([]byte, error) {
+	return asset{{.Ident}}.Bytes()
+}
+{{end}}`))
+
+type file struct {
+	Name       string
+	Ident      string
+	Ciphertext []byte
+}
+
+type source struct {
+	Package string
+	Env     string
+	Prompt  bool
+	Files   []file
+}
+
+// generate writes the Go source file which embeds the encrypted files to w.
+func generate(w io.Writer, src *source) error {
+	if !token.IsIdentifier(src.Package) {
+		return fmt.Errorf("invalid package name %q", src.Package)
+	}
+
+	// Each file also declares a variable named "asset" followed by the
+	// function name, which is unique as long as the function names are.
+	seen := make(map[string]string, len(src.Files))
+
+	for _, f := range src.Files {
+		if reserved[f.Ident] {
+			return fmt.Errorf("%v has the function name %v, which is reserved", f.Name, f.Ident)
+		}
+
+		if prev, ok := seen[f.Ident]; ok {
+			return fmt.Errorf("%v and %v have the same function name %v", prev, f.Name, f.Ident)
+		}
+
+		seen[f.Ident] = f.Name
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, src); err != nil {
+		return err
+	}
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+
+	return err
+}
+
+// identifier returns the exported function name for the file name, such as
+// ConfigJSON for "config.json".
+func identifier(name string) (string, error) {
+	words := strings.FieldsFunc(filepath.Base(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+
+			continue
+		}
+
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	ident := b.String()
+	if ident != "" && !unicode.IsUpper([]rune(ident)[0]) {
+		ident = "File" + ident
+	}
+
+	if !token.IsIdentifier(ident) {
+		return "", fmt.Errorf("could not derive a function name from %v", name)
+	}
+
+	return ident, nil
+}
+
+// literal returns a Go string literal of b which is split into lines.
+func literal(b []byte) string {
+	if len(b) == 0 {
+		return `""`
+	}
+
+	var s strings.Builder
+
+	for i := 0; i < len(b); i += bytesPerLine {
+		if i > 0 {
+			s.WriteString(" +\n\t")
+		}
+
+		s.WriteByte('"')
+
+		for _, c := range b[i:min(i+bytesPerLine, len(b))] {
+			fmt.Fprintf(&s, `\x%02x`, c)
+		}
+
+		s.WriteByte('"')
+	}
+
+	return s.String()
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIdentifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"config.json", "ConfigJSON"},
+		{"testdata/server-key.pem", "ServerKeyPEM"},
+		{"1st_secret.txt", "File1stSecretTxt"},
+		{"index.html", "IndexHTML"},
+	}
+
+	for _, tt := range tests {
+		ident, err := identifier(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ident != tt.expected {
+			t.Errorf("expected identifier of `%v` `%v`, got `%v`", tt.name, tt.expected, ident)
+		}
+	}
+
+	if _, err := identifier("..."); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := make([]byte, 100)
+	for i := range ciphertext {
+		ciphertext[i] = byte(i)
+	}
+
+	src := source{
+		Package: "secrets",
+		Env:     "APP_PASSPHRASE",
+		Prompt:  true,
+		Files:   []file{{"config.json", "ConfigJSON", ciphertext}, {"empty.txt", "EmptyTxt", nil}},
+	}
+
+	var b strings.Builder
+	if err := generate(&b, &src); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), "", b.String(), parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name := f.Name.Name; name != "secrets" {
+		t.Errorf("expected package name `%v`, got `%v`", "secrets", name)
+	}
+
+	for _, name := range []string{"PassphraseSource", "ConfigJSON", "EmptyTxt"} {
+		if f.Scope.Lookup(name) == nil {
+			t.Errorf("expected `%v` to be declared", name)
+		}
+	}
+
+	if !strings.Contains(b.String(), strconv.Quote("APP_PASSPHRASE")) {
+		t.Error("expected environment variable name")
+	}
+}
+
+func TestGenerateDuplicateIdentifier(t *testing.T) {
+	t.Parallel()
+
+	src := source{
+		Package: "secrets",
+		Env:     "APP_PASSPHRASE",
+		Files:   []file{{"a/config.json", "ConfigJSON", nil}, {"b/config.json", "ConfigJSON", nil}},
+	}
+
+	var b strings.Builder
+	if err := generate(&b, &src); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	ident, err := identifier("passphrase-source")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src.Files = []file{{"passphrase-source", ident, nil}}
+	if err := generate(&b, &src); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected error for reserved name, got `%v`", err)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	t.Parallel()
+
+	data := make([]byte, 70)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	if _, err := parser.ParseExpr(literal(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	var s strings.Builder
+
+	for _, lit := range strings.Split(literal(data), "+") {
+		v, err := strconv.Unquote(strings.TrimSpace(lit))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s.WriteString(v)
+	}
+
+	if s.String() != string(data) {
+		t.Errorf("expected literal of `%v`, got `%v`", data, []byte(s.String()))
+	}
+}
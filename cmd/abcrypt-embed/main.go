@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+	"golang.org/x/term"
+)
+
+func main() {
+	flag.Parse()
+
+	if opt.version {
+		fmt.Printf("abcrypt-go %v\n", cli.Version)
+		os.Exit(0)
+	}
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	pkg := opt.pkg
+	if pkg == "" {
+		pkg = "main"
+	}
+
+	params, err := opt.params()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	encOpts := []abcrypt.Option{
+		abcrypt.WithArgon2Type(opt.argon2Type),
+		abcrypt.WithParams(params.MemoryCost, params.TimeCost, uint8(params.Parallelism)),
+	}
+
+	passphrase := []byte(os.Getenv(opt.env))
+	if len(passphrase) == 0 {
+		passphrase, err = readPassphrase()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	src := source{Package: pkg, Env: opt.env, Prompt: !opt.noPrompt}
+
+	for _, name := range flag.Args() {
+		plaintext, err := os.ReadFile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ident, err := identifier(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), plaintext, passphrase, encOpts...)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		src.Files = append(src.Files, file{filepath.ToSlash(name), ident, ciphertext})
+	}
+
+	var buf bytes.Buffer
+	if err := generate(&buf, &src); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writeFile(opt.output, buf.Bytes()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readPassphrase prompts for the passphrase twice on the terminal, and
+// reports an error if they do not match.
+func readPassphrase() ([]byte, error) {
+	fmt.Fprint(os.Stderr, "Enter passphrase: ")
+
+	passphrase, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr)
+
+	fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+
+	confirm, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return nil, err
+	}
+	defer clear(confirm)
+	fmt.Fprintln(os.Stderr)
+
+	if !bytes.Equal(passphrase, confirm) {
+		clear(passphrase)
+
+		return nil, errors.New("passphrases do not match")
+	}
+
+	return passphrase, nil
+}
+
+// writeFile writes data to a temporary file in the same directory as name,
+// and renames it into place, so the generated file is never left partially
+// written.
+func writeFile(name string, data []byte) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// os.CreateTemp creates the file with mode 0600.
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, name)
+}
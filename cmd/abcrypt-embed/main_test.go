@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "abcrypt_assets.go")
+
+	if err := os.WriteFile(name, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFile(name, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "new" {
+		t.Errorf("expected `%v`, got `%s`", "new", b)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected the temporary file to be renamed, got `%v` entries", len(entries))
+	}
+
+	if err := writeFile(filepath.Join(dir, "missing", "abcrypt_assets.go"), nil); err == nil {
+		t.Error("expected error for the missing directory")
+	}
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+const (
+	defaultSize = 64
+	minSize     = 32
+)
+
+type options struct {
+	size    uint
+	force   bool
+	version bool
+}
+
+var opt options
+
+func init() {
+	flag.UintVar(&opt.size, "size", defaultSize, "Set the size of the keyfile in bytes")
+	flag.BoolVar(&opt.force, "force", false, "Overwrite the keyfile if it already exists")
+	flag.BoolVar(&opt.version, "version", false, "Print version number")
+
+	flag.Usage = func() {
+		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] <FILE>\n", os.Args[0]); err != nil {
+			log.Fatal(err)
+		}
+
+		flag.PrintDefaults()
+	}
+}
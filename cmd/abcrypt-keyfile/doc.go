@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Abcrypt-keyfile generates a keyfile filled with random bytes, which is used
+// as a second factor with the -keyfile option of the encrypt and decrypt
+// examples.
+//
+// The keyfile is created with permission 0600, and an existing file is not
+// overwritten unless -force is specified. The size must be at least 32 bytes.
+//
+// Usage:
+//
+//	abcrypt-keyfile [OPTIONS] <FILE>
+//
+// Arguments:
+//
+//	<FILE>
+//		Output file.
+//
+// Options:
+//
+//	-size <NUM>
+//		Set the size of the keyfile in bytes.
+//	-force
+//		Overwrite the keyfile if it already exists.
+//	-version
+//		Print version number.
+package main
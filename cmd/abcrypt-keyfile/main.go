@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+)
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+
+	if opt.version {
+		fmt.Printf("abcrypt-go %v\n", cli.Version)
+		os.Exit(0)
+	}
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if opt.size < minSize {
+		log.Fatalf("the size of the keyfile must be at least %v bytes", minSize)
+	}
+
+	keyfile := make([]byte, opt.size)
+	if _, err := rand.Read(keyfile); err != nil {
+		log.Fatal(err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if opt.force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(args[0], flags, 0o600)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := f.Write(keyfile); err != nil {
+		_ = f.Close()
+
+		log.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// maxTimeCost is the upper bound of the time cost searched by calibrate.
+const maxTimeCost = 1 << 16
+
+var calibrateCommand = &command{
+	name:    "calibrate",
+	args:    "[OPTIONS]",
+	summary: "Find the Argon2 parameters whose key derivation takes the target duration on this machine",
+	run:     runCalibrate,
+}
+
+type calibration struct {
+	Argon2Type abcrypt.Argon2Type `json:"argon2Type"`
+	Params     abcrypt.Params     `json:"params"`
+	Elapsed    time.Duration      `json:"elapsed"`
+}
+
+func runCalibrate(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	target := fs.Duration("target", time.Second, "Set the target duration of the key derivation")
+	asJSON := fs.Bool("json", false, "Output the result as JSON")
+	kdf := addKDFFlags(fs)
+
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	if *target <= 0 {
+		return &usageError{err: fmt.Errorf("invalid target duration %v", *target)}
+	}
+
+	// Calibrate the time cost only, starting from one iteration.
+	kdf.timeCost = 1
+
+	p, err := kdf.params()
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	elapsed, err := measure(ctx, kdf.argon2Type, p)
+	if err != nil {
+		return err
+	}
+
+	// If a single iteration is too slow, halve the memory cost instead.
+	minMemoryCost := 8 * p.Parallelism
+	for elapsed > *target && p.MemoryCost/2 >= minMemoryCost {
+		p.MemoryCost /= 2
+
+		if elapsed, err = measure(ctx, kdf.argon2Type, p); err != nil {
+			return err
+		}
+	}
+
+	// The duration is roughly proportional to the time cost, but a short run
+	// is dominated by the allocation, so refine the estimate a few times.
+	for range 4 {
+		perIteration := max(elapsed/time.Duration(p.TimeCost), 1)
+		timeCost := uint32(min(max(int64(*target/perIteration), 1), maxTimeCost))
+
+		if timeCost == p.TimeCost {
+			break
+		}
+
+		p.TimeCost = timeCost
+
+		if elapsed, err = measure(ctx, kdf.argon2Type, p); err != nil {
+			return err
+		}
+	}
+
+	result := calibration{kdf.argon2Type, p, elapsed}
+
+	if *asJSON {
+		return json.NewEncoder(a.stdout).Encode(result)
+	}
+
+	_, err = fmt.Fprintf(a.stdout, "Argon2 type: %v\nParameters: %v\nElapsed: %v\n", result.Argon2Type, result.Params, result.Elapsed.Round(time.Millisecond))
+
+	return err
+}
+
+// measure returns the duration of a key derivation with the given Argon2 type
+// and Argon2 parameters.
+func measure(ctx context.Context, argon2Type abcrypt.Argon2Type, p abcrypt.Params) (time.Duration, error) {
+	var salt [32]byte
+
+	start := time.Now()
+
+	if _, err := (abcrypt.Argon2KeyDeriver{}).DeriveKey(ctx, nil, salt[:], argon2Type, p); err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+)
+
+// Exit codes. The other exit codes are mapped from the errors by
+// [cli.ExitCode].
+const (
+	exitSuccess = cli.ExitSuccess
+	exitFailure = cli.ExitFailure
+	exitUsage   = cli.ExitUsage
+)
+
+// command represents a subcommand.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []*command{
+	encryptCommand,
+	decryptCommand,
+	infoCommand,
+	dumpCommand,
+	verifyCommand,
+	rekeyCommand,
+	editCommand,
+	execCommand,
+	packCommand,
+	unpackCommand,
+	vaultCommand,
+	calibrateCommand,
+}
+
+// app represents the environment in which the command runs.
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// prompt reads a passphrase from the user after showing the prompt.
+	prompt func(prompt string) ([]byte, error)
+
+	// jsonErrors reports whether the error is printed as a JSON object.
+	jsonErrors bool
+}
+
+func newApp() *app {
+	a := app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, prompt: promptTerminal}
+
+	return &a
+}
+
+// usageError represents an error due to the command line was invalid.
+type usageError struct {
+	err error
+
+	// reported reports whether the error has already been printed with the
+	// usage.
+	reported bool
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+func (e *usageError) ExitCode() int {
+	return exitUsage
+}
+
+func (a *app) run(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("abcrypt", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	version := fs.Bool("version", false, "Print version number")
+	fs.BoolVar(&a.jsonErrors, "json-errors", false, "Print errors as JSON objects")
+	fs.Usage = func() { a.usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+
+		return exitUsage
+	}
+
+	if *version {
+		fmt.Fprintf(a.stdout, "abcrypt-go %v\n", cli.Version)
+
+		return exitSuccess
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+
+		return exitUsage
+	}
+
+	name, args := fs.Arg(0), fs.Args()[1:]
+
+	if name == "help" {
+		if len(args) == 0 {
+			fs.SetOutput(a.stdout)
+			fs.Usage()
+
+			return exitSuccess
+		}
+
+		name, args = args[0], []string{"-help"}
+	}
+
+	cmd := lookup(name)
+	if cmd == nil {
+		fmt.Fprintf(a.stderr, "abcrypt: unknown command %q\n", name)
+		fmt.Fprintln(a.stderr, "Run 'abcrypt help' for usage.")
+
+		return a.exit(&usageError{err: fmt.Errorf("unknown command %q", name), reported: true})
+	}
+
+	return a.exit(cmd.run(ctx, a, a.newFlagSet(cmd), args))
+}
+
+func (a *app) usage(fs *flag.FlagSet) {
+	w := fs.Output()
+
+	fmt.Fprintln(w, "Usage: abcrypt [OPTIONS] <COMMAND> [ARGS]...")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %v\n", cmd.name, cmd.summary)
+	}
+
+	fmt.Fprintf(w, "  %-10s %v\n", "help", "Print the usage of a command")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Options:")
+	fs.PrintDefaults()
+}
+
+// exit reports err and returns the exit code.
+func (a *app) exit(err error) int {
+	if err == nil {
+		return exitSuccess
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return exitSuccess
+	}
+
+	// The exit status of the command run by exec is passed through.
+	var statusErr *exitStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) && usageErr.reported && !a.jsonErrors {
+		return exitUsage
+	}
+
+	if a.jsonErrors {
+		b, _ := json.Marshal(cli.NewErrorReport(err))
+		fmt.Fprintln(a.stderr, string(b))
+	} else {
+		fmt.Fprintf(a.stderr, "Error: %v\n", err)
+	}
+
+	return cli.ExitCode(err)
+}
+
+// fileError represents an error of a file in JSON.
+type fileError struct {
+	File string `json:"file"`
+	*cli.ErrorReport
+}
+
+// writeFileError writes the error of the file to w, as a line of JSON if
+// asJSON is true.
+func writeFileError(w io.Writer, asJSON bool, name string, err error) error {
+	if asJSON {
+		return json.NewEncoder(w).Encode(fileError{name, cli.NewErrorReport(err)})
+	}
+
+	_, err = fmt.Fprintf(w, "Error: %v: %v\n", name, err)
+
+	return err
+}
+
+func lookup(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+// newFlagSet creates a new [flag.FlagSet] for the subcommand.
+func (a *app) newFlagSet(cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet("abcrypt "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+
+	fs.Usage = func() {
+		w := fs.Output()
+
+		fmt.Fprintf(w, "Usage: abcrypt %v %v\n", cmd.name, cmd.args)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, cmd.summary+".")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Options:")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parse parses the arguments of the subcommand, and checks that the number of
+// the positional arguments is between minArgs and maxArgs. If maxArgs is
+// negative, the number of the positional arguments is not limited.
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return &usageError{err: err, reported: true}
+	}
+
+	if n := fs.NArg(); n < minArgs || (maxArgs >= 0 && n > maxArgs) {
+		fs.Usage()
+
+		return &usageError{err: fmt.Errorf("invalid number of arguments: %v", n), reported: true}
+	}
+
+	return nil
+}
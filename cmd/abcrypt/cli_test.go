@@ -0,0 +1,616 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+)
+
+const data = "Hello, world!\n"
+
+var fastParams = []string{"-memory-cost", "32", "-time-cost", "3", "-parallelism", "4"}
+
+type result struct {
+	code   int
+	stdout []byte
+	stderr string
+}
+
+// runApp runs the command with stdin and answers the passphrase prompts with
+// passphrases in order.
+func runApp(t *testing.T, stdin []byte, passphrases []string, args ...string) *result {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	a := app{
+		stdin:  bytes.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		prompt: func(string) ([]byte, error) {
+			if len(passphrases) == 0 {
+				return nil, errors.New("unexpected prompt")
+			}
+
+			p := passphrases[0]
+			passphrases = passphrases[1:]
+
+			return []byte(p), nil
+		},
+	}
+
+	code := a.run(context.Background(), args)
+
+	return &result{code, stdout.Bytes(), stderr.String()}
+}
+
+func encryptData(t *testing.T, passphrase string) []byte {
+	t.Helper()
+
+	r := runApp(t, []byte(data), []string{passphrase, passphrase}, append([]string{"encrypt"}, fastParams...)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	return r.stdout
+}
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+
+	if r := runApp(t, nil, nil); r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+
+	if r := runApp(t, nil, nil, "unknown"); r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+
+	if r := runApp(t, nil, nil, "decrypt", "a", "b"); r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+
+	if r := runApp(t, nil, nil, "help"); r.code != exitSuccess || !bytes.Contains(r.stdout, []byte("calibrate")) {
+		t.Errorf("expected usage, got `%v` `%s`", r.code, r.stdout)
+	}
+
+	if r := runApp(t, nil, nil, "encrypt", "-argon2-type", "argon2d"); r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encryptData(t, "passphrase")
+
+	header, err := abcrypt.NewHeader(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p := header.Params(); p != (abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}) {
+		t.Errorf("unexpected parameters `%v`", p)
+	}
+
+	r := runApp(t, ciphertext, []string{"passphrase"}, "decrypt")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if string(r.stdout) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, r.stdout)
+	}
+
+	if r := runApp(t, ciphertext, []string{"password"}, "decrypt"); r.code != cli.ExitInvalidPassphrase {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitInvalidPassphrase, r.code)
+	}
+}
+
+func TestDecryptFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "data.txt.abcrypt")
+	output := filepath.Join(dir, "data.txt")
+
+	if err := os.WriteFile(input, encryptData(t, "passphrase"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := runApp(t, nil, []string{"passphrase"}, "decrypt", "-output", output, input); r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	plaintext, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, plaintext)
+	}
+}
+
+func TestDecryptFileWithInvalidMAC(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "data.txt")
+
+	ciphertext := encryptData(t, "passphrase")
+	ciphertext[len(ciphertext)-1] ^= 1
+
+	if r := runApp(t, ciphertext, []string{"passphrase"}, "decrypt", "-output", output); r.code != cli.ExitCorrupted {
+		t.Fatalf("expected exit code `%v`, got `%v`", cli.ExitCorrupted, r.code)
+	}
+
+	if _, err := os.Stat(output); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected output to be removed, got `%v`", err)
+	}
+}
+
+func TestOutputFileExists(t *testing.T) {
+	t.Parallel()
+
+	output := filepath.Join(t.TempDir(), "data.txt.abcrypt")
+	if err := os.WriteFile(output, []byte("existing"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The command fails before prompting for the passphrase.
+	args := append([]string{"encrypt", "-output", output}, fastParams...)
+	if r := runApp(t, []byte(data), nil, args...); r.code != cli.ExitIO {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitIO, r.code)
+	}
+
+	args = append([]string{"encrypt", "-output", output, "-force", "-verify"}, fastParams...)
+	if r := runApp(t, []byte(data), []string{"passphrase", "passphrase"}, args...); r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	ciphertext, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := abcrypt.Decrypt(ciphertext, []byte("passphrase")); err != nil {
+		t.Error(err)
+	}
+
+	if r := runApp(t, ciphertext, nil, "decrypt", "-verify"); r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+}
+
+func TestEncryptDecryptLargeData(t *testing.T) {
+	t.Parallel()
+
+	plaintext := bytes.Repeat([]byte(data), 100000)
+
+	r := runApp(t, plaintext, []string{"passphrase", "passphrase"}, append([]string{"encrypt"}, fastParams...)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if n := len(r.stdout); n != abcrypt.HeaderSize+len(plaintext)+abcrypt.TagSize {
+		t.Errorf("expected ciphertext length `%v`, got `%v`", abcrypt.HeaderSize+len(plaintext)+abcrypt.TagSize, n)
+	}
+
+	r = runApp(t, r.stdout, []string{"passphrase"}, "decrypt")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if !bytes.Equal(r.stdout, plaintext) {
+		t.Error("unexpected mismatch between plaintext and data")
+	}
+}
+
+func TestInfo(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encryptData(t, "passphrase")
+
+	r := runApp(t, ciphertext, nil, "info")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if !bytes.Contains(r.stdout, []byte("m=32,t=3,p=4")) {
+		t.Errorf("unexpected output `%s`", r.stdout)
+	}
+
+	r = runApp(t, ciphertext, nil, "info", "-json")
+
+	var header abcrypt.Header
+	if err := json.Unmarshal(r.stdout, &header); err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Argon2Type != abcrypt.Argon2id {
+		t.Errorf("expected Argon2 type `%v`, got `%v`", abcrypt.Argon2id, header.Argon2Type)
+	}
+
+	if r := runApp(t, []byte(data), nil, "info"); r.code != cli.ExitNotAbcrypt {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitNotAbcrypt, r.code)
+	}
+}
+
+func TestInfoBatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ciphertext := encryptData(t, "passphrase")
+
+	for _, name := range []string{"a.abcrypt", "sub/b.abcrypt", "sub/ignored.txt"} {
+		name = filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(name, ciphertext, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	invalid := filepath.Join(dir, "invalid")
+	if err := os.WriteFile(invalid, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := runApp(t, nil, nil, "info", "-json", dir, invalid)
+	if r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(r.stdout), []byte("\n"))
+	if n := len(lines); n != 3 {
+		t.Fatalf("expected number of lines `%v`, got `%v`: %s", 3, n, r.stdout)
+	}
+
+	var info struct {
+		File          string           `json:"file"`
+		Salt          abcrypt.HexBytes `json:"salt"`
+		PayloadLength int64            `json:"payloadLength"`
+		KDFMemory     uint64           `json:"kdfMemory"`
+		Error         string           `json:"error"`
+	}
+
+	if err := json.Unmarshal(lines[1], &info); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.File != filepath.Join(dir, "sub", "b.abcrypt") {
+		t.Errorf("expected file `%v`, got `%v`", filepath.Join(dir, "sub", "b.abcrypt"), info.File)
+	}
+
+	if !bytes.Equal(info.Salt, ciphertext[28:60]) {
+		t.Errorf("unexpected salt `%v`", info.Salt)
+	}
+
+	if info.PayloadLength != int64(len(data)) {
+		t.Errorf("expected payload length `%v`, got `%v`", len(data), info.PayloadLength)
+	}
+
+	if info.KDFMemory != 32*1024 {
+		t.Errorf("expected KDF memory `%v`, got `%v`", 32*1024, info.KDFMemory)
+	}
+
+	if err := json.Unmarshal(lines[2], &info); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.File != invalid || info.Error != abcrypt.ErrInvalidLength.Error() {
+		t.Errorf("unexpected line `%s`", lines[2])
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encryptData(t, "passphrase")
+
+	r := runApp(t, ciphertext, []string{"password"}, "-json-errors", "decrypt")
+	if r.code != cli.ExitInvalidPassphrase {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitInvalidPassphrase, r.code)
+	}
+
+	var report cli.ErrorReport
+	if err := json.Unmarshal([]byte(r.stderr), &report); err != nil {
+		t.Fatalf("%v: %s", err, r.stderr)
+	}
+
+	expected := cli.ErrorReport{Error: "abcrypt: invalid header MAC", Kind: "invalid-passphrase", Code: cli.ExitInvalidPassphrase}
+	if report != expected {
+		t.Errorf("expected error `%+v`, got `%+v`", expected, report)
+	}
+
+	r = runApp(t, nil, nil, "-json-errors", "decrypt", "a", "b")
+	if r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(r.stderr), "\n")
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &report); err != nil || report.Kind != "usage" {
+		t.Errorf("unexpected error `%s`", r.stderr)
+	}
+}
+
+func TestUnsupportedKDF(t *testing.T) {
+	t.Parallel()
+
+	names := []string{
+		"../../testdata/v1/argon2d/v0x10/data.txt.abcrypt",
+		"../../testdata/v1/argon2d/v0x13/data.txt.abcrypt",
+		"../../testdata/v1/argon2id/v0x10/data.txt.abcrypt",
+	}
+
+	// The number of iterations of 0, and the degree of parallelism of 0 and
+	// 256.
+	for i, tc := range []struct {
+		offset int
+		value  uint32
+	}{
+		{20, 0},
+		{24, 0},
+		{24, 256},
+	} {
+		ciphertext := encryptData(t, "passphrase")
+		binary.LittleEndian.PutUint32(ciphertext[tc.offset:tc.offset+4], tc.value)
+
+		name := filepath.Join(t.TempDir(), fmt.Sprintf("params%v.abcrypt", i))
+		if err := os.WriteFile(name, ciphertext, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, name)
+	}
+
+	for _, name := range names {
+		for _, args := range [][]string{
+			{"decrypt", name},
+			{"verify", name},
+			{"rekey", name},
+			{"unpack", name, filepath.Join(t.TempDir(), "dir")},
+			{"vault", "ls", name},
+			{"vault", "set", name, "token"},
+		} {
+			r := runApp(t, nil, []string{"passphrase", "passphrase"}, append([]string{"-json-errors"}, args...)...)
+			if r.code != cli.ExitUnsupported {
+				t.Errorf("expected exit code of `%v` for `%v` `%v`, got `%v`: %v", args, name, cli.ExitUnsupported, r.code, r.stderr)
+
+				continue
+			}
+
+			var report cli.ErrorReport
+			if err := json.Unmarshal([]byte(r.stderr), &report); err != nil || report.Kind != "unsupported" {
+				t.Errorf("unexpected error `%s`: %v", r.stderr, err)
+			}
+		}
+	}
+}
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encryptData(t, "passphrase")
+
+	r := runApp(t, ciphertext, nil, "dump")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	for _, s := range []string{
+		`00000000  61 62 63 72 79 70 74                             magic number: "abcrypt"`,
+		"00000008  02 00 00 00                                      Argon2 type: argon2id",
+		"00000010  20 00 00 00                                      memory cost: 32 KiB",
+		"00000094  ",
+		fmt.Sprintf("%08x  ", len(ciphertext)-abcrypt.TagSize),
+		fmt.Sprintf("Total: %v bytes (payload %v bytes)", len(ciphertext), len(data)),
+	} {
+		if !bytes.Contains(r.stdout, []byte(s)) {
+			t.Errorf("expected output to contain `%v`, got `%s`", s, r.stdout)
+		}
+	}
+
+	if bytes.Contains(r.stdout, []byte("invalid")) {
+		t.Errorf("unexpected invalid field in `%s`", r.stdout)
+	}
+
+	// Argon2d is unsupported, but is a valid Argon2 type.
+	argon2d := bytes.Clone(ciphertext)
+	binary.LittleEndian.PutUint32(argon2d[8:12], 0)
+
+	r = runApp(t, argon2d, nil, "dump")
+	if !bytes.Contains(r.stdout, []byte("Argon2 type: argon2d\n")) {
+		t.Errorf("expected valid Argon2 type, got `%s`", r.stdout)
+	}
+
+	ciphertext[7] = 0
+	binary.LittleEndian.PutUint32(ciphertext[8:12], 3)
+	binary.LittleEndian.PutUint32(ciphertext[12:16], 0x14)
+
+	r = runApp(t, ciphertext, nil, "dump")
+	if r.code != cli.ExitUnsupported {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitUnsupported, r.code)
+	}
+
+	for _, s := range []string{
+		"version: 0 [invalid: abcrypt: unsupported version number `0`]",
+		"Argon2 type: Argon2Type(3) [invalid: abcrypt: invalid Argon2 type]",
+		"Argon2 version: 0x14 [invalid: abcrypt: invalid Argon2 version `0x14`]",
+	} {
+		if !bytes.Contains(r.stdout, []byte(s)) {
+			t.Errorf("expected output to contain `%v`, got `%s`", s, r.stdout)
+		}
+	}
+
+	r = runApp(t, ciphertext[:100], nil, "dump")
+	if !bytes.Contains(r.stdout, []byte("(truncated to 16 bytes)")) || !bytes.Contains(r.stdout, []byte("salt")) {
+		t.Errorf("unexpected output `%s`", r.stdout)
+	}
+}
+
+func TestDumpPayload(t *testing.T) {
+	t.Parallel()
+
+	plaintext := bytes.Repeat([]byte(data), 100)
+
+	r := runApp(t, plaintext, []string{"passphrase", "passphrase"}, append([]string{"encrypt"}, fastParams...)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	ciphertext := r.stdout
+
+	r = runApp(t, ciphertext, nil, "dump")
+
+	omitted := fmt.Sprintf("(%v more bytes of the payload)", len(plaintext)-64)
+	if !bytes.Contains(r.stdout, []byte(omitted)) {
+		t.Errorf("expected output to contain `%v`, got `%s`", omitted, r.stdout)
+	}
+
+	r = runApp(t, ciphertext, nil, "dump", "-all")
+	if bytes.Contains(r.stdout, []byte("more bytes")) {
+		t.Errorf("unexpected omitted payload in `%s`", r.stdout)
+	}
+
+	// The column headings, the header, the payload, the tag and the total.
+	lines := bytes.Count(r.stdout, []byte("\n"))
+	if expected := 1 + 15 + (len(plaintext)+15)/16 + 1 + 1; lines != expected {
+		t.Errorf("expected `%v` lines, got `%v`", expected, lines)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encryptData(t, "passphrase")
+
+	if r := runApp(t, ciphertext, []string{"passphrase"}, "verify"); r.code != exitSuccess || len(r.stdout) != 0 {
+		t.Errorf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	ciphertext[len(ciphertext)-1] ^= 1
+
+	if r := runApp(t, ciphertext, []string{"passphrase"}, "verify"); r.code != cli.ExitCorrupted {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitCorrupted, r.code)
+	}
+}
+
+func TestRekey(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encryptData(t, "passphrase")
+
+	r := runApp(t, ciphertext, []string{"passphrase", "new passphrase", "new passphrase"}, "rekey")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	header, err := abcrypt.NewHeader(r.stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p := header.Params(); p != (abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}) {
+		t.Errorf("expected parameters to be kept, got `%v`", p)
+	}
+
+	plaintext, err := abcrypt.Decrypt(r.stdout, []byte("new passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, plaintext)
+	}
+
+	r = runApp(t, ciphertext, []string{"passphrase", "passphrase", "passphrase"}, "rekey", "-time-cost", "4", "-memory-cost", "64")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if p, _ := abcrypt.NewParams(r.stdout); p.TimeCost != 4 || p.MemoryCost != 64 {
+		t.Errorf("expected new parameters, got `%v`", p)
+	}
+}
+
+func TestRekeyOutput(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "data.txt.abcrypt")
+	if err := os.WriteFile(name, encryptData(t, "passphrase"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := runApp(t, nil, []string{"passphrase", "new passphrase", "new passphrase"}, "rekey", "-output", name, name)
+	if r.code != cli.ExitIO {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", cli.ExitIO, r.code, r.stderr)
+	}
+
+	if !strings.Contains(r.stderr, "-force") {
+		t.Errorf("expected a hint about -force, got `%v`", r.stderr)
+	}
+
+	r = runApp(t, nil, []string{"passphrase", "new passphrase", "new passphrase"}, "rekey", "-output", name, "-force", "-verify", name)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	ciphertext, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := abcrypt.Decrypt(ciphertext, []byte("new passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, plaintext)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	t.Parallel()
+
+	r := runApp(t, nil, nil, "calibrate", "-target", "10ms", "-memory-cost", "64", "-json")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	var c calibration
+	if err := json.Unmarshal(r.stdout, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Params.Validate(); err != nil {
+		t.Errorf("expected valid parameters, got `%v`", err)
+	}
+
+	if c.Params.MemoryCost > 64 {
+		t.Errorf("expected memory cost at most `%v`, got `%v`", 64, c.Params.MemoryCost)
+	}
+
+	if r := runApp(t, nil, nil, "calibrate", "-target", "0s"); r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+}
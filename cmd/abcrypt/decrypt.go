@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var decryptCommand = &command{
+	name:    "decrypt",
+	args:    "[OPTIONS] [FILE|-r DIR]",
+	summary: "Decrypt a file or a directory tree",
+	run:     runDecrypt,
+}
+
+func runDecrypt(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	output := addOutputFlags(fs)
+	tree := addTreeFlags(fs)
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	if err := tree.check(fs); err != nil {
+		return err
+	}
+
+	if tree.recursive {
+		return a.decryptTree(ctx, tree, output, key, fs.Arg(0))
+	}
+
+	if err := output.check(); err != nil {
+		return err
+	}
+
+	f, err := a.openInput(inputName(fs))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	in := bufio.NewReader(f)
+
+	// Reject a file which cannot be decrypted before prompting.
+	header, _ := in.Peek(abcrypt.HeaderSize + abcrypt.TagSize)
+	if _, err := checkHeader(header); err != nil {
+		return err
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return err
+	}
+
+	if !isStdio(output.name) {
+		return abcrypt.DecryptFile(ctx, output.name, in, passphrase, append(opts, output.options()...)...)
+	}
+
+	r, err := abcrypt.NewReader(ctx, in, passphrase, opts...)
+	if err != nil {
+		return err
+	}
+
+	return a.writeStdout(func(out io.Writer) error {
+		_, err := io.Copy(out, r)
+
+		return err
+	})
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Abcrypt is a command-line utility for the abcrypt encrypted data format.
+//
+// The input file is given as the positional argument. If it is omitted or is
+// "-", the standard input is used. The result is written to the standard
+// output unless -output is specified. encrypt, decrypt and verify process the
+// data in chunks, so pipelines run in constant memory. Since the MAC of the
+// ciphertext is at the end of the data, decrypt may have written a part of the
+// plaintext to the standard output before it detects an invalid MAC, and exits
+// with a failure.
+//
+// The output file of encrypt, decrypt and rekey is written to a temporary
+// file in the same directory, which is synced and renamed into place only
+// after the whole data has been processed, so it is never left partially
+// written or unauthenticated. An existing file is not overwritten unless
+// -force is specified, and -verify reads back the temporary file and checks it
+// before renaming. The decrypted file is created with mode 0600.
+//
+// With -r, encrypt and decrypt process the regular files in the directory tree
+// given as the argument. encrypt writes each file to the same name with the
+// ".abcrypt" extension and skips the files which already have it, and decrypt
+// processes only the files which have the extension and removes it. With
+// -output, the output files are written to the same relative paths in the
+// given directory instead of next to the input files. -include and -exclude
+// select the files by glob patterns, which are matched against both the
+// slash-separated path relative to the directory and the file name, and may
+// be repeated; an excluded directory is not descended into. The passphrase is
+// prompted for only once. The files are processed concurrently by as many
+// workers as the key derivations fit in -memory-budget (KiB, 1 GiB by
+// default), and the total memory of the concurrent key derivations never
+// exceeds it. A file which fails is reported and the others are still
+// processed, and a summary is printed at the end.
+//
+// The passphrase is read from the first source below which is available:
+//
+//   - -passphrase-from-env, -passphrase-from-file or -passphrase-from-fd.
+//     The file and the file descriptor are read up to the first newline.
+//   - -askpass or -pinentry, which prompt with the given askpass program or
+//     GnuPG pinentry program.
+//   - abcrypt-agent, if ABCRYPT_AGENT_SOCK is set and -keyfile is not
+//     specified.
+//   - A prompt with the program specified by ABCRYPT_ASKPASS or
+//     ABCRYPT_PINENTRY, or on the controlling terminal (/dev/tty), so the
+//     standard input can be used for the data. If no terminal is available,
+//     the program specified by SSH_ASKPASS is used instead. When encrypting,
+//     the passphrase is prompted for twice.
+//
+// rekey accepts the same flags prefixed with "new-" for the new passphrase.
+//
+// Usage:
+//
+//	abcrypt [OPTIONS] <COMMAND> [ARGS]...
+//
+// Commands:
+//
+//	encrypt [OPTIONS] [FILE|-r DIR]
+//		Encrypt a file or a directory tree.
+//	decrypt [OPTIONS] [FILE|-r DIR]
+//		Decrypt a file or a directory tree.
+//	info [OPTIONS] [FILE|DIR]...
+//		Print the header of encrypted files: the format version, the
+//		Argon2 type, version and parameters, the salt, the nonce and the
+//		header MAC in hexadecimal, the payload length and the memory
+//		required for the key derivation. A directory is searched
+//		recursively for files with the ".abcrypt" extension. -json
+//		outputs a line of JSON for each file (JSON Lines), with an
+//		"error" member for a file which could not be read.
+//	dump [OPTIONS] [FILE]
+//		Print an annotated hexdump of the header following the offsets of
+//		the format, then the payload and the tag. A field which the
+//		parser rejects is flagged as invalid, and the command fails. Only
+//		the first 64 bytes of the payload are printed unless -all is
+//		specified. The MACs are not verified.
+//	verify [OPTIONS] [FILE]
+//		Check that a file decrypts successfully without writing the
+//		plaintext.
+//	rekey [OPTIONS] [FILE]
+//		Re-encrypt a file with a new passphrase or new Argon2 parameters.
+//		The Argon2 type and parameters of the file are kept unless any of
+//		them is specified.
+//	edit [OPTIONS] <FILE>
+//		Decrypt a file to a private temporary file (mode 0600, on a tmpfs
+//		such as $XDG_RUNTIME_DIR or /dev/shm where available), open it
+//		with the editor given by -editor, $VISUAL or $EDITOR, and
+//		re-encrypt the file in place with the same Argon2 type and
+//		parameters only if the contents changed. The temporary file is a
+//		regular file, not a memfd, so without a tmpfs it is written to
+//		the system temporary directory. The temporary files are
+//		overwritten with zeros and removed afterwards. If the file was
+//		modified before the re-encrypted file is renamed into place, it
+//		is left as is and the edited contents are saved to FILE.conflict
+//		instead.
+//	exec [OPTIONS] -env <FILE> [--] <COMMAND> [ARGS]...
+//		Decrypt a dotenv file and run the command with its variables
+//		added to the environment. The variables are kept in memory and
+//		passed only to the command, never written to the disk. The
+//		signals received by abcrypt are forwarded to the command, except
+//		for SIGINT and SIGQUIT while in the foreground, which the
+//		terminal sends to the command itself. abcrypt exits with the exit
+//		status of the command, with 128 plus the signal number if it was
+//		terminated by a signal, or with 127 if it could not be run. The
+//		dotenv file consists of KEY=VALUE lines, optionally preceded by
+//		"export", with single-quoted (literal) or double-quoted (with
+//		escape sequences) values which may span multiple lines, and "#"
+//		comments. Variables are not expanded.
+//	pack [OPTIONS] <DIR> [FILE]
+//		Encrypt a directory tree as a single tar archive, so neither the
+//		names, the sizes nor the structure of the files are visible. The
+//		directories, the regular files and the symbolic links are archived
+//		with their modes and modification times. If FILE is omitted or is
+//		"-", the archive is written to the standard output.
+//	unpack [OPTIONS] <FILE> <DIR>
+//		Decrypt an archive created by pack into DIR, which must not exist.
+//		The archive is extracted into a temporary directory, which is
+//		renamed to DIR only after the MAC of the ciphertext has been
+//		verified. An entry whose name or link target would be outside DIR
+//		is refused.
+//	vault <COMMAND> [OPTIONS] <FILE> [NAME]...
+//		Manage the named secrets stored in a single encrypted vault file,
+//		so the key derivation runs once for all of them. The commands are
+//		get (print the value of a secret), set (set the value of a secret
+//		to the standard input, creating the vault with the Argon2 flags if
+//		it does not exist), ls (list the names of the secrets) and rm
+//		(remove secrets). The vault is saved atomically, and concurrent
+//		commands on the same vault wait for each other with the lock file
+//		FILE.lock.
+//	calibrate [OPTIONS]
+//		Find the Argon2 parameters whose key derivation takes the target
+//		duration (-target) on this machine. The memory cost is kept and
+//		the time cost is adjusted, unless a single iteration is already
+//		too slow.
+//	help [COMMAND]
+//		Print the usage of a command.
+//
+// Options:
+//
+//	-json-errors
+//		Print the error to the standard error as a JSON object with the
+//		"error" (message), "kind" and "code" (exit status) members, for
+//		example {"error":"abcrypt: invalid header MAC",
+//		"kind":"invalid-passphrase","code":3}.
+//	-version
+//		Print version number.
+//
+// Exit status:
+//
+//	0	Success.
+//	1	An error occurred ("failure").
+//	2	The command line was invalid ("usage").
+//	3	The passphrase or the keyfile was wrong, or the header was
+//		modified ("invalid-passphrase").
+//	4	The payload was corrupted or truncated ("corrupted").
+//	5	The data was not in the abcrypt encrypted data format
+//		("not-abcrypt").
+//	6	The version of the format, the Argon2 type or version, or the
+//		degree of parallelism was not supported ("unsupported"), such as
+//		for a file encrypted with Argon2d.
+//	7	A file could not be read or written ("io").
+package main
@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var dumpCommand = &command{
+	name:    "dump",
+	args:    "[OPTIONS] [FILE]",
+	summary: "Print an annotated hexdump of the structure of an encrypted file",
+	run:     runDump,
+}
+
+// dumpLineSize is the number of bytes in a line of the hexdump.
+const dumpLineSize = 16
+
+// dumpPreviewSize is the number of bytes of the payload which is printed
+// unless -all is specified.
+const dumpPreviewSize = 64
+
+// dumpField represents a field of the header.
+type dumpField struct {
+	name       string
+	start, end int
+
+	// describe returns the decoded value of the field, and the error which
+	// makes the parser reject it.
+	describe func(b []byte) (string, error)
+}
+
+// dumpFields are the fields of the header in the order of the offsets.
+var dumpFields = []dumpField{
+	{"magic number", 0, 7, func(b []byte) (string, error) {
+		if string(b) != "abcrypt" {
+			return fmt.Sprintf("%q", b), abcrypt.ErrInvalidMagicNumber
+		}
+
+		return fmt.Sprintf("%q", b), nil
+	}},
+	{"version", 7, 8, func(b []byte) (string, error) {
+		switch v := b[0]; v {
+		case 0:
+			return fmt.Sprint(v), &abcrypt.UnsupportedVersionError{Version: v}
+		case 1:
+			return fmt.Sprint(v), nil
+		default:
+			return fmt.Sprint(v), &abcrypt.UnknownVersionError{Version: v}
+		}
+	}},
+	{"Argon2 type", 8, 12, func(b []byte) (string, error) {
+		t := abcrypt.Argon2Type(binary.LittleEndian.Uint32(b))
+
+		// Argon2d is not exported, but is also valid. The Argon2 types are
+		// numbered from 0 (Argon2d) to Argon2id.
+		if t > abcrypt.Argon2id {
+			return t.String(), &abcrypt.InvalidArgon2TypeError{Variant: uint32(t)}
+		}
+
+		return t.String(), nil
+	}},
+	{"Argon2 version", 12, 16, func(b []byte) (string, error) {
+		v := abcrypt.Argon2Version(binary.LittleEndian.Uint32(b))
+		if v != abcrypt.Argon2Version0x10 && v != abcrypt.Argon2Version0x13 {
+			return v.String(), &abcrypt.InvalidArgon2VersionError{Version: uint32(v)}
+		}
+
+		return v.String(), nil
+	}},
+	{"memory cost", 16, 20, func(b []byte) (string, error) {
+		return fmt.Sprintf("%v KiB", binary.LittleEndian.Uint32(b)), nil
+	}},
+	{"time cost", 20, 24, func(b []byte) (string, error) {
+		return fmt.Sprint(binary.LittleEndian.Uint32(b)), nil
+	}},
+	{"parallelism", 24, 28, func(b []byte) (string, error) {
+		return fmt.Sprint(binary.LittleEndian.Uint32(b)), nil
+	}},
+	{"salt", 28, 60, nil},
+	{"nonce (XChaCha20-Poly1305)", 60, 84, nil},
+	{"header MAC (BLAKE2b-512 of bytes 0..84, not verified)", 84, abcrypt.HeaderSize, nil},
+}
+
+func runDump(_ context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	all := fs.Bool("all", false, fmt.Sprintf("Print the whole payload instead of the first %v bytes", dumpPreviewSize))
+
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	f, err := a.openInput(inputName(fs))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, abcrypt.HeaderSize+abcrypt.TagSize)
+
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	buf = buf[:n]
+
+	if _, err := fmt.Fprintf(a.stdout, "%-8v  %-47v  %v\n", "Offset", "Bytes", "Field"); err != nil {
+		return err
+	}
+
+	for _, field := range dumpFields {
+		if err := dumpHeaderField(a.stdout, buf, field); err != nil {
+			return err
+		}
+	}
+
+	if len(buf) < abcrypt.HeaderSize {
+		if _, err := fmt.Fprintf(a.stdout, "Total: %v bytes\n", len(buf)); err != nil {
+			return err
+		}
+
+		_, err = abcrypt.NewHeader(buf)
+
+		return err
+	}
+
+	p := payloadDumper{w: a.stdout, limit: dumpPreviewSize}
+	if *all {
+		p.limit = -1
+	}
+
+	// The last 16 bytes are held back until the end of the data, since they
+	// are the tag rather than the payload.
+	pending := append([]byte(nil), buf[abcrypt.HeaderSize:]...)
+
+	if len(buf) == abcrypt.HeaderSize+abcrypt.TagSize {
+		chunk := make([]byte, 32*1024)
+
+		for {
+			n, err := f.Read(chunk)
+			pending = append(pending, chunk[:n]...)
+
+			if len(pending) > abcrypt.TagSize {
+				if err := p.write(pending[:len(pending)-abcrypt.TagSize]); err != nil {
+					return err
+				}
+
+				pending = append(pending[:0], pending[len(pending)-abcrypt.TagSize:]...)
+			}
+
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := p.flush(); err != nil {
+		return err
+	}
+
+	tag := "MAC (Poly1305 tag of the payload, not verified)"
+	if len(pending) < abcrypt.TagSize {
+		tag = fmt.Sprintf("MAC (truncated to %v bytes) [invalid: %v]", len(pending), abcrypt.ErrInvalidLength)
+	}
+
+	if err := dumpBytes(a.stdout, abcrypt.HeaderSize+p.length, pending, tag); err != nil {
+		return err
+	}
+
+	total := abcrypt.HeaderSize + p.length + int64(len(pending))
+	if _, err := fmt.Fprintf(a.stdout, "Total: %v bytes (payload %v bytes)\n", total, p.length); err != nil {
+		return err
+	}
+
+	// The result of the parser is the exit status, so that the flagged fields
+	// are reported as an error.
+	_, err = abcrypt.NewHeader(buf)
+
+	return err
+}
+
+// dumpHeaderField prints a field of the header, and flags it if the parser
+// rejects it or it is truncated.
+func dumpHeaderField(w io.Writer, buf []byte, field dumpField) error {
+	if len(buf) <= field.start {
+		return dumpBytes(w, int64(field.start), nil, field.name+" (missing)")
+	}
+
+	b := buf[field.start:min(field.end, len(buf))]
+	note := field.name
+
+	switch {
+	case len(b) < field.end-field.start:
+		note += fmt.Sprintf(" (truncated to %v bytes) [invalid: %v]", len(b), abcrypt.ErrInvalidLength)
+	case field.describe != nil:
+		value, err := field.describe(b)
+		note += ": " + value
+
+		if err != nil {
+			note += fmt.Sprintf(" [invalid: %v]", err)
+		}
+	}
+
+	return dumpBytes(w, int64(field.start), b, note)
+}
+
+// dumpBytes prints b in lines of 16 bytes, and the note on the first line.
+func dumpBytes(w io.Writer, offset int64, b []byte, note string) error {
+	for {
+		line := b[:min(len(b), dumpLineSize)]
+		b = b[len(line):]
+
+		if err := dumpLine(w, offset, line, note); err != nil {
+			return err
+		}
+
+		offset += int64(len(line))
+		note = ""
+
+		if len(b) == 0 {
+			return nil
+		}
+	}
+}
+
+func dumpLine(w io.Writer, offset int64, b []byte, note string) error {
+	hex := make([]string, len(b))
+	for i, c := range b {
+		hex[i] = fmt.Sprintf("%02x", c)
+	}
+
+	line := fmt.Sprintf("%08x  %-47v  %v", offset, strings.Join(hex, " "), note)
+	_, err := fmt.Fprintln(w, strings.TrimRight(line, " "))
+
+	return err
+}
+
+// payloadDumper prints the payload line by line as it is read, up to the
+// limit.
+type payloadDumper struct {
+	w io.Writer
+
+	// limit is the number of bytes to print, or a negative value for all of
+	// them.
+	limit int64
+
+	// length is the number of bytes of the payload which have been written.
+	length int64
+
+	line []byte
+}
+
+func (p *payloadDumper) write(b []byte) error {
+	for len(b) > 0 {
+		if p.limit >= 0 && p.length >= p.limit {
+			p.length += int64(len(b))
+
+			return nil
+		}
+
+		n := min(len(b), dumpLineSize-len(p.line))
+		if p.limit >= 0 {
+			n = min(n, int(p.limit-p.length))
+		}
+
+		p.line = append(p.line, b[:n]...)
+		p.length += int64(n)
+		b = b[n:]
+
+		if len(p.line) == dumpLineSize {
+			if err := p.printLine(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *payloadDumper) printLine() error {
+	shown := p.length
+	if p.limit >= 0 {
+		shown = min(shown, p.limit)
+	}
+
+	offset := shown - int64(len(p.line))
+
+	note := ""
+	if offset == 0 {
+		note = "payload (XChaCha20-Poly1305 ciphertext)"
+	}
+
+	err := dumpLine(p.w, int64(abcrypt.HeaderSize)+offset, p.line, note)
+	p.line = p.line[:0]
+
+	return err
+}
+
+func (p *payloadDumper) flush() error {
+	if len(p.line) > 0 {
+		if err := p.printLine(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.length == 0:
+		return dumpLine(p.w, abcrypt.HeaderSize, nil, "payload (empty)")
+	case p.limit >= 0 && p.length > p.limit:
+		_, err := fmt.Fprintf(p.w, "%-8v  %-47v  (%v more bytes of the payload)\n", "*", "", p.length-p.limit)
+
+		return err
+	default:
+		return nil
+	}
+}
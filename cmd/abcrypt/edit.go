@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var editCommand = &command{
+	name:    "edit",
+	args:    "[OPTIONS] <FILE>",
+	summary: "Edit an encrypted file in place with an editor",
+	run:     runEdit,
+}
+
+func runEdit(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	editor := fs.String("editor", "", "Use the editor command instead of $VISUAL or $EDITOR")
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	header, err := checkHeader(ciphertext)
+	if err != nil {
+		return err
+	}
+
+	params, err := abcrypt.NewParams(ciphertext)
+	if err != nil {
+		return err
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := abcrypt.DecryptWithOptions(ctx, ciphertext, passphrase, opts...)
+	if err != nil {
+		return err
+	}
+	defer clear(plaintext)
+
+	edited, err := a.editPlaintext(ctx, editorCommand(*editor), name, plaintext)
+	if err != nil {
+		return err
+	}
+	defer clear(edited)
+
+	if bytes.Equal(edited, plaintext) {
+		fmt.Fprintf(a.stderr, "%v is unchanged\n", name)
+
+		return nil
+	}
+
+	// The file is re-encrypted with the same Argon2 type and Argon2
+	// parameters, and a new salt and a new nonce.
+	encOpts := append(opts,
+		abcrypt.WithArgon2Type(header.Argon2Type),
+		abcrypt.WithParams(params.MemoryCost, params.TimeCost, uint8(params.Parallelism)),
+	)
+
+	return saveEdited(ctx, name, info.Mode().Perm(), ciphertext, edited, passphrase, encOpts)
+}
+
+// saveEdited encrypts the edited contents into a temporary file next to the
+// named file, and renames it into place. Since the key derivation takes a
+// while, the file is compared with the original ciphertext only just before
+// renaming, and if it was modified by someone else while editing, the edited
+// contents are kept in FILE.conflict instead of overwriting the changes.
+func saveEdited(ctx context.Context, name string, perm fs.FileMode, ciphertext, edited, passphrase []byte, opts []abcrypt.Option) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	if err := abcrypt.EncryptFile(ctx, tmp, bytes.NewReader(edited), passphrase, append(opts, abcrypt.WithOverwrite())...); err != nil {
+		return err
+	}
+
+	// Keep the mode of the file regardless of the umask.
+	if err := os.Chmod(tmp, perm); err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if bytes.Equal(current, ciphertext) {
+		return os.Rename(tmp, name)
+	}
+
+	conflict := name + ".conflict"
+	if _, err := os.Lstat(conflict); err == nil {
+		return fmt.Errorf("%v was modified while editing, and the edited contents could not be saved: %w", name, &fs.PathError{Op: "rename", Path: conflict, Err: fs.ErrExist})
+	}
+
+	if err := os.Rename(tmp, conflict); err != nil {
+		return fmt.Errorf("%v was modified while editing, and the edited contents could not be saved: %w", name, err)
+	}
+
+	return fmt.Errorf("%v was modified while editing; the edited contents are saved to %v", name, conflict)
+}
+
+// editorCommand returns the editor command. Unless it is specified by the
+// flag, $VISUAL, $EDITOR and the default editor of the platform are tried in
+// order.
+func editorCommand(flagValue string) string {
+	for _, s := range []string{flagValue, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if strings.TrimSpace(s) != "" {
+			return s
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	return "vi"
+}
+
+// editPlaintext writes plaintext to a private temporary file, runs the editor
+// on it, and returns the edited contents. The temporary files are wiped
+// afterwards, including the ones created by the editor, such as swap files.
+func (a *app) editPlaintext(ctx context.Context, editor, name string, plaintext []byte) (edited []byte, err error) {
+	dir, err := os.MkdirTemp(privateTempDir(), "abcrypt-edit-*")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err = errors.Join(err, wipeDir(dir))
+	}()
+
+	// Keep the extension of the plaintext, so the editor can detect the type
+	// of the file.
+	tmp := filepath.Join(dir, strings.TrimSuffix(filepath.Base(name), ".abcrypt"))
+	if err := os.WriteFile(tmp, plaintext, 0o600); err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(editor)
+
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], tmp)...)
+	cmd.Stdin = a.stdin
+	cmd.Stdout = a.stdout
+	cmd.Stderr = a.stderr
+
+	// The error is not wrapped, so the exit status of the editor is not
+	// mistaken for the exit code of abcrypt.
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %q failed; %v is unchanged: %v", editor, name, err)
+	}
+
+	return os.ReadFile(tmp)
+}
+
+// privateTempDir returns the directory for the plaintext. A tmpfs, which is
+// never written to the disk, is preferred where available.
+func privateTempDir() string {
+	for _, dir := range []string{os.Getenv("XDG_RUNTIME_DIR"), "/dev/shm"} {
+		if dir == "" {
+			continue
+		}
+
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+
+	return os.TempDir()
+}
+
+// wipeDir overwrites the regular files in dir with zeros before removing dir.
+func wipeDir(dir string) error {
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		return wipeFile(name)
+	})
+
+	return errors.Join(err, os.RemoveAll(dir))
+}
+
+func wipeFile(name string) error {
+	f, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(make([]byte, info.Size())); err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// writeEditor creates a shell script used as the editor, which records the
+// name of the temporary file to the returned file before running script.
+func writeEditor(t *testing.T, script string) (string, string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the editor is a shell script")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	editor := filepath.Join(dir, "editor")
+
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\necho \"$1\" > '"+log+"'\n"+script+"\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	return editor, log
+}
+
+// checkWiped checks that the temporary file recorded by the editor has been
+// removed.
+func checkWiped(t *testing.T, log string) {
+	t.Helper()
+
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := strings.TrimSpace(string(b))
+	if filepath.Base(tmp) != "secret.txt" {
+		t.Errorf("expected the temporary file `secret.txt`, got `%v`", tmp)
+	}
+
+	if _, err := os.Stat(filepath.Dir(tmp)); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary directory to be removed, got `%v`", err)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	t.Parallel()
+
+	editor, log := writeEditor(t, `printf 'edited\n' >> "$1"`)
+
+	name := filepath.Join(t.TempDir(), "secret.txt.abcrypt")
+	if err := os.WriteFile(name, encryptData(t, "passphrase"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	r := runApp(t, nil, []string{"passphrase"}, "edit", "-editor", editor, name)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	checkWiped(t, log)
+
+	ciphertext, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := abcrypt.Decrypt(ciphertext, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := data + "edited\n"; string(plaintext) != expected {
+		t.Errorf("expected plaintext `%v`, got `%s`", expected, plaintext)
+	}
+
+	params, err := abcrypt.NewParams(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}); *params != expected {
+		t.Errorf("expected Argon2 parameters `%v`, got `%v`", expected, params)
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o640 {
+		t.Errorf("expected mode `%v`, got `%v`", os.FileMode(0o640), info.Mode().Perm())
+	}
+}
+
+func TestEditUnchanged(t *testing.T) {
+	t.Parallel()
+
+	editor, log := writeEditor(t, "true")
+
+	name := filepath.Join(t.TempDir(), "secret.txt.abcrypt")
+	ciphertext := encryptData(t, "passphrase")
+
+	if err := os.WriteFile(name, ciphertext, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := runApp(t, nil, []string{"passphrase"}, "edit", "-editor", editor, name)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	checkWiped(t, log)
+
+	if b, _ := os.ReadFile(name); !bytes.Equal(b, ciphertext) {
+		t.Error("expected the file not to be re-encrypted")
+	}
+
+	// A failed editor leaves the file unchanged.
+	editor, log = writeEditor(t, `printf 'edited\n' >> "$1"; exit 1`)
+
+	if r := runApp(t, nil, []string{"passphrase"}, "edit", "-editor", editor, name); r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	checkWiped(t, log)
+
+	if b, _ := os.ReadFile(name); !bytes.Equal(b, ciphertext) {
+		t.Error("expected the file not to be re-encrypted")
+	}
+}
+
+func TestEditConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "secret.txt.abcrypt")
+	if err := os.WriteFile(name, encryptData(t, "passphrase"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	modified := encryptData(t, "passphrase")
+	other := name + ".other"
+
+	if err := os.WriteFile(other, modified, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	editor, _ := writeEditor(t, `printf 'edited\n' >> "$1"; cp '`+other+`' '`+name+`'`)
+
+	r := runApp(t, nil, []string{"passphrase"}, "edit", "-editor", editor, name)
+	if r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	if !strings.Contains(r.stderr, "was modified while editing") {
+		t.Errorf("unexpected error output `%v`", r.stderr)
+	}
+
+	if b, _ := os.ReadFile(name); !bytes.Equal(b, modified) {
+		t.Error("expected the concurrent modification to be kept")
+	}
+
+	ciphertext, err := os.ReadFile(name + ".conflict")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plaintext, err := abcrypt.Decrypt(ciphertext, []byte("passphrase")); err != nil || string(plaintext) != data+"edited\n" {
+		t.Errorf("unexpected edited contents `%s`: %v", plaintext, err)
+	}
+}
+
+func TestSaveEditedModifiedWhileEncrypting(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "secret.txt.abcrypt")
+	ciphertext := encryptData(t, "passphrase")
+
+	if err := os.WriteFile(name, ciphertext, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	modified := encryptData(t, "passphrase")
+
+	// Modify the file during the key derivation of the re-encryption.
+	deriver := keyDeriverFunc(func(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+		if err := os.WriteFile(name, modified, 0o600); err != nil {
+			return nil, err
+		}
+
+		return abcrypt.Argon2KeyDeriver{}.DeriveKey(ctx, passphrase, salt, argon2Type, params)
+	})
+
+	opts := []abcrypt.Option{abcrypt.WithParams(32, 3, 4), abcrypt.WithKeyDeriver(deriver)}
+
+	if err := saveEdited(context.Background(), name, 0o600, ciphertext, []byte("edited\n"), []byte("passphrase"), opts); err == nil || !strings.Contains(err.Error(), "was modified while editing") {
+		t.Errorf("unexpected error `%v`", err)
+	}
+
+	if b, _ := os.ReadFile(name); !bytes.Equal(b, modified) {
+		t.Error("expected the concurrent modification to be kept")
+	}
+
+	conflict, err := os.ReadFile(name + ".conflict")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plaintext, err := abcrypt.Decrypt(conflict, []byte("passphrase")); err != nil || string(plaintext) != "edited\n" {
+		t.Errorf("unexpected edited contents `%s`: %v", plaintext, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("unexpected temporary file `%v`", e.Name())
+		}
+	}
+}
+
+type keyDeriverFunc func(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error)
+
+func (f keyDeriverFunc) DeriveKey(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+	return f(ctx, passphrase, salt, argon2Type, params)
+}
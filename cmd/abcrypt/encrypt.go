@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var encryptCommand = &command{
+	name:    "encrypt",
+	args:    "[OPTIONS] [FILE|-r DIR]",
+	summary: "Encrypt a file or a directory tree",
+	run:     runEncrypt,
+}
+
+func runEncrypt(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	output := addOutputFlags(fs)
+	tree := addTreeFlags(fs)
+	kdf := addKDFFlags(fs)
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	if err := tree.check(fs); err != nil {
+		return err
+	}
+
+	if tree.recursive {
+		return a.encryptTree(ctx, tree, output, key, kdf, fs.Arg(0))
+	}
+
+	opts, err := kdf.options()
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	if err := output.check(); err != nil {
+		return err
+	}
+
+	in, err := a.openInput(inputName(fs))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	passphrase, keyOpts, err := a.credentials(ctx, key, true)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, keyOpts...)
+
+	if !isStdio(output.name) {
+		return abcrypt.EncryptFile(ctx, output.name, in, passphrase, append(opts, output.options()...)...)
+	}
+
+	return a.writeStdout(func(out io.Writer) error {
+		w, err := abcrypt.NewWriter(ctx, out, passphrase, opts...)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(w, in); err != nil {
+			return err
+		}
+
+		return w.Close()
+	})
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var execCommand = &command{
+	name:    "exec",
+	args:    "[OPTIONS] -env <FILE> [--] <COMMAND> [ARGS]...",
+	summary: "Run a command with the variables of an encrypted dotenv file",
+	run:     runExec,
+}
+
+// exitStatusError represents the exit status of the command run by exec,
+// which becomes the exit code of abcrypt without printing an error.
+type exitStatusError struct {
+	code int
+}
+
+func (e *exitStatusError) Error() string {
+	return fmt.Sprintf("command exited with status %v", e.code)
+}
+
+func (e *exitStatusError) ExitCode() int {
+	return e.code
+}
+
+// startError represents an error due to the command run by exec could not be
+// started, such as when it was not found. As in the shell, abcrypt exits with
+// status 127.
+type startError struct {
+	err error
+}
+
+func (e *startError) Error() string {
+	return e.err.Error()
+}
+
+func (e *startError) Unwrap() error {
+	return e.err
+}
+
+func (e *startError) ExitCode() int {
+	return 127
+}
+
+func runExec(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	envFile := fs.String("env", "", "Decrypt the dotenv file and add its variables to the environment of the command")
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	if *envFile == "" {
+		fs.Usage()
+
+		return &usageError{err: errors.New("-env is required"), reported: true}
+	}
+
+	ciphertext, err := a.readInput(*envFile)
+	if err != nil {
+		return err
+	}
+
+	if _, err := checkHeader(ciphertext); err != nil {
+		return err
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return err
+	}
+
+	env, err := abcrypt.LoadEnvWithOptions(ctx, ciphertext, passphrase, opts...)
+	if err != nil {
+		return fmt.Errorf("%v: %w", *envFile, err)
+	}
+
+	// The variables are passed only to the environment of the command, and
+	// override the inherited ones.
+	environ := os.Environ()
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		environ = append(environ, k+"="+env[k])
+	}
+
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	cmd.Env = environ
+	cmd.Stdin = a.stdin
+	cmd.Stdout = a.stdout
+	cmd.Stderr = a.stderr
+
+	return runForwardingSignals(cmd)
+}
+
+// runForwardingSignals runs the command, forwards the signals received by
+// abcrypt to it, and returns its exit status as an [exitStatusError] if it is
+// not zero.
+func runForwardingSignals(cmd *exec.Cmd) error {
+	// The buffer holds a burst of different signals, which are delivered
+	// before the goroutine forwarding them gets to run.
+	signals := make(chan os.Signal, 16)
+	signal.Notify(signals, forwardedSignals...)
+
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return &startError{err}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				forwardSignal(cmd.Process, sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &exitStatusError{exitStatus(exitErr.ProcessState)}
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// forwardedSignals are the signals which exec forwards to the command.
+var forwardedSignals = []os.Signal{
+	syscall.SIGHUP,
+	syscall.SIGINT,
+	syscall.SIGQUIT,
+	syscall.SIGTERM,
+	syscall.SIGUSR1,
+	syscall.SIGUSR2,
+	syscall.SIGWINCH,
+}
+
+// forwardSignal sends the signal to the command. SIGINT and SIGQUIT from the
+// keyboard are sent by the terminal to the command as well, since it is in the
+// same process group, so they are not forwarded while the process group is in
+// the foreground.
+func forwardSignal(p *os.Process, sig os.Signal) {
+	if (sig == syscall.SIGINT || sig == syscall.SIGQUIT) && inForeground() {
+		return
+	}
+
+	_ = p.Signal(sig)
+}
+
+// inForeground reports whether the process group of abcrypt is the foreground
+// process group of the controlling terminal.
+func inForeground() bool {
+	f, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	pgrp, err := unix.IoctlGetInt(int(f.Fd()), unix.TIOCGPGRP)
+
+	return err == nil && pgrp == unix.Getpgrp()
+}
+
+// exitStatus returns the exit status of the command. A command terminated by
+// a signal exits with 128 plus the signal number as in the shell.
+func exitStatus(state *os.ProcessState) int {
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+
+	return state.ExitCode()
+}
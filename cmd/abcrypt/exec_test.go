@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// writeEnv encrypts the dotenv format data to a file.
+func writeEnv(t *testing.T, env string) string {
+	t.Helper()
+
+	r := runApp(t, []byte(env), []string{"passphrase", "passphrase"}, append([]string{"encrypt"}, fastParams...)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	name := filepath.Join(t.TempDir(), "secrets.env.abcrypt")
+	if err := os.WriteFile(name, r.stdout, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is a shell script")
+	}
+
+	name := writeEnv(t, "SECRET='s3cr3t value'\nexport OTHER=\"a\\nb\"\n")
+
+	r := runApp(t, nil, []string{"passphrase"}, "exec", "-env", name, "--", "sh", "-c", `printf '%s|%s' "$SECRET" "$OTHER"`)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if expected := "s3cr3t value|a\nb"; string(r.stdout) != expected {
+		t.Errorf("expected output `%v`, got `%s`", expected, r.stdout)
+	}
+
+	if _, ok := os.LookupEnv("SECRET"); ok {
+		t.Error("expected the variables not to be set in abcrypt")
+	}
+}
+
+func TestExecExitStatus(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is a shell script")
+	}
+
+	name := writeEnv(t, "A=1\n")
+
+	r := runApp(t, nil, []string{"passphrase"}, "exec", "-env", name, "sh", "-c", "exit 42")
+	if r.code != 42 {
+		t.Errorf("expected exit code `%v`, got `%v`", 42, r.code)
+	}
+
+	if r.stderr != "" {
+		t.Errorf("expected no error output, got `%v`", r.stderr)
+	}
+
+	// A command terminated by a signal exits with 128 plus the signal number.
+	if r := runApp(t, nil, []string{"passphrase"}, "exec", "-env", name, "sh", "-c", "kill -TERM $$"); r.code != 128+15 {
+		t.Errorf("expected exit code `%v`, got `%v`", 128+15, r.code)
+	}
+}
+
+func TestExecCommandNotFound(t *testing.T) {
+	t.Parallel()
+
+	name := writeEnv(t, "A=1\n")
+
+	r := runApp(t, nil, []string{"passphrase"}, "exec", "-env", name, filepath.Join(t.TempDir(), "not-found"))
+	if r.code != 127 {
+		t.Errorf("expected exit code `%v`, got `%v`", 127, r.code)
+	}
+
+	if !strings.Contains(r.stderr, "not-found") {
+		t.Errorf("unexpected error output `%v`", r.stderr)
+	}
+}
+
+func TestExecWithInvalidEnv(t *testing.T) {
+	t.Parallel()
+
+	name := writeEnv(t, "A=1\nB='unterminated\n")
+
+	r := runApp(t, nil, []string{"passphrase"}, "exec", "-env", name, "true")
+	if r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	if expected := (&abcrypt.ParseEnvError{Line: 2, Reason: "unterminated quoted value"}).Error(); !strings.Contains(r.stderr, expected) {
+		t.Errorf("expected error output to contain `%v`, got `%v`", expected, r.stderr)
+	}
+
+	for _, args := range [][]string{{"exec", "true"}, {"exec", "-env", name}} {
+		if r := runApp(t, nil, nil, args...); r.code != exitUsage {
+			t.Errorf("expected exit code of `%v` `%v`, got `%v`", args, exitUsage, r.code)
+		}
+	}
+}
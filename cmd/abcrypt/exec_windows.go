@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build windows
+
+package main
+
+import "os"
+
+// forwardedSignals are the signals which exec forwards to the command.
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// forwardSignal does nothing, since the console delivers Ctrl+C to the
+// command as well, and an interrupt cannot be sent to another process.
+func forwardSignal(*os.Process, os.Signal) {}
+
+func exitStatus(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+const (
+	defaultMemoryCost  = 19456
+	defaultTimeCost    = 2
+	defaultParallelism = 1
+)
+
+// kdfFlags represents the flags for the Argon2 type and the Argon2
+// parameters.
+type kdfFlags struct {
+	argon2Type  abcrypt.Argon2Type
+	memoryCost  uint
+	timeCost    uint
+	parallelism uint
+}
+
+func addKDFFlags(fs *flag.FlagSet) *kdfFlags {
+	f := kdfFlags{argon2Type: abcrypt.Argon2id}
+
+	fs.Var(&f.argon2Type, "argon2-type", "Set the Argon2 type (argon2i or argon2id)")
+	fs.UintVar(&f.memoryCost, "memory-cost", defaultMemoryCost, "Set the memory size in KiB")
+	fs.UintVar(&f.timeCost, "time-cost", defaultTimeCost, "Set the number of iterations")
+	fs.UintVar(&f.parallelism, "parallelism", defaultParallelism, "Set the degree of parallelism")
+
+	return &f
+}
+
+// params returns the Argon2 parameters after validating them.
+func (f *kdfFlags) params() (abcrypt.Params, error) {
+	if f.argon2Type != abcrypt.Argon2i && f.argon2Type != abcrypt.Argon2id {
+		return abcrypt.Params{}, fmt.Errorf("%v is not supported for encryption", f.argon2Type)
+	}
+
+	if f.memoryCost > math.MaxUint32 || f.timeCost > math.MaxUint32 || f.parallelism > math.MaxUint32 {
+		return abcrypt.Params{}, errors.New("the Argon2 parameters are out of range")
+	}
+
+	p := abcrypt.Params{MemoryCost: uint32(f.memoryCost), TimeCost: uint32(f.timeCost), Parallelism: uint32(f.parallelism)}
+	if err := p.Validate(); err != nil {
+		return abcrypt.Params{}, err
+	}
+
+	return p, nil
+}
+
+// options returns the options for the encryptor.
+func (f *kdfFlags) options() ([]abcrypt.Option, error) {
+	p, err := f.params()
+	if err != nil {
+		return nil, err
+	}
+
+	return []abcrypt.Option{abcrypt.WithArgon2Type(f.argon2Type), abcrypt.WithParams(p.MemoryCost, p.TimeCost, uint8(p.Parallelism))}, nil
+}
+
+// visited reports whether any flag of the Argon2 type and the Argon2
+// parameters was set.
+func (f *kdfFlags) visited(fs *flag.FlagSet) bool {
+	var set bool
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "argon2-type", "memory-cost", "time-cost", "parallelism":
+			set = true
+		}
+	})
+
+	return set
+}
@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var infoCommand = &command{
+	name:    "info",
+	args:    "[OPTIONS] [FILE|DIR]...",
+	summary: "Print the header of encrypted files",
+	run:     runInfo,
+}
+
+// headerInfo represents the information about an encrypted file.
+type headerInfo struct {
+	File string `json:"file,omitempty"`
+	*abcrypt.Header
+
+	// PayloadLength represents the length of the plaintext in bytes.
+	PayloadLength int64 `json:"payloadLength"`
+
+	// KDFMemory represents the memory in bytes required for the key
+	// derivation.
+	KDFMemory uint64 `json:"kdfMemory"`
+}
+
+func runInfo(_ context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	asJSON := fs.Bool("json", false, "Output the header of each file as a line of JSON (JSON Lines)")
+
+	if err := parse(fs, args, 0, -1); err != nil {
+		return err
+	}
+
+	names, err := infoInputs(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+
+	for i, name := range names {
+		info, err := a.readHeaderInfo(name)
+		if err != nil {
+			// A single input fails with its own error, as the other commands.
+			if len(names) == 1 {
+				return err
+			}
+
+			failed++
+
+			// With -json, the error is a line of the output.
+			w := a.stderr
+			if *asJSON {
+				w = a.stdout
+			}
+
+			if err := writeFileError(w, *asJSON, name, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if *asJSON {
+			err = json.NewEncoder(a.stdout).Encode(info)
+		} else {
+			if i > 0 {
+				fmt.Fprintln(a.stdout)
+			}
+
+			err = printHeaderInfo(a.stdout, info)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("could not read %v of %v files", failed, len(names))
+	}
+
+	return nil
+}
+
+// infoInputs expands the arguments into the input files. A directory is
+// walked recursively for the files which have the abcrypt file extension.
+func infoInputs(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{"-"}, nil
+	}
+
+	var names []string
+
+	for _, arg := range args {
+		if isStdio(arg) {
+			names = append(names, "-")
+
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			// A missing file is reported when it is read.
+			names = append(names, arg)
+
+			continue
+		}
+
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.Type().IsRegular() && filepath.Ext(path) == abcrypt.FileExtension {
+				names = append(names, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
+
+// readHeaderInfo reads the header of the file or the standard input. Only the
+// header is read from a file, and its length is taken from the file system.
+func (a *app) readHeaderInfo(name string) (*headerInfo, error) {
+	var (
+		r    io.Reader
+		size int64 = -1
+	)
+
+	if isStdio(name) {
+		r = a.stdin
+	} else {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		fi, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+
+		r, size = f, fi.Size()
+	}
+
+	buf := make([]byte, abcrypt.HeaderSize+abcrypt.TagSize)
+
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	header, err := abcrypt.NewHeader(buf[:n])
+	if err != nil {
+		return nil, err
+	}
+
+	if size < 0 {
+		m, err := io.Copy(io.Discard, r)
+		if err != nil {
+			return nil, err
+		}
+
+		size = int64(n) + m
+	}
+
+	info := headerInfo{
+		Header:        header,
+		PayloadLength: size - abcrypt.HeaderSize - abcrypt.TagSize,
+		KDFMemory:     header.Params().MemoryBytes(),
+	}
+
+	if !isStdio(name) {
+		info.File = name
+	}
+
+	return &info, nil
+}
+
+func printHeaderInfo(w io.Writer, info *headerInfo) error {
+	if info.File != "" {
+		if _, err := fmt.Fprintf(w, "File: %v\n", info.File); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, `Format version: %v
+Argon2 type: %v
+Argon2 version: %v
+Parameters: %v
+Salt: %v
+Nonce: %v
+Header MAC: %v
+Payload length: %v bytes
+KDF memory: %v bytes (%v MiB)
+`,
+		info.Version,
+		info.Argon2Type,
+		info.Argon2Version,
+		info.Params(),
+		info.Salt,
+		info.Nonce,
+		info.MAC,
+		info.PayloadLength,
+		info.KDFMemory,
+		info.KDFMemory>>20,
+	)
+
+	return err
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// isStdio reports whether name refers to the standard input or the standard
+// output.
+func isStdio(name string) bool {
+	return name == "" || name == "-"
+}
+
+// inputName returns the input file given as the first positional argument,
+// or "-" for the standard input.
+func inputName(fs *flag.FlagSet) string {
+	if fs.NArg() == 0 {
+		return "-"
+	}
+
+	return fs.Arg(0)
+}
+
+// readInput reads the file or the standard input.
+func (a *app) readInput(name string) ([]byte, error) {
+	if isStdio(name) {
+		return io.ReadAll(a.stdin)
+	}
+
+	return os.ReadFile(name)
+}
+
+// openInput opens the file or the standard input for streaming.
+func (a *app) openInput(name string) (io.ReadCloser, error) {
+	if isStdio(name) {
+		return io.NopCloser(a.stdin), nil
+	}
+
+	return os.Open(name)
+}
+
+// outputFlags represents the flags for the output file of encrypt, decrypt
+// and rekey.
+type outputFlags struct {
+	name   string
+	force  bool
+	verify bool
+}
+
+func addOutputFlags(fs *flag.FlagSet) *outputFlags {
+	var f outputFlags
+
+	fs.StringVar(&f.name, "output", "", "Output the result to a file")
+	fs.BoolVar(&f.force, "force", false, "Overwrite the output file if it exists")
+	fs.BoolVar(&f.verify, "verify", false, "Read back the output file and check it before renaming it into place")
+
+	return &f
+}
+
+// check reports an error before prompting for the passphrase if the output
+// file exists.
+func (f *outputFlags) check() error {
+	if isStdio(f.name) {
+		if f.verify {
+			return &usageError{err: errors.New("-verify requires -output")}
+		}
+
+		return nil
+	}
+
+	if _, err := os.Lstat(f.name); err == nil && !f.force {
+		err := fs.PathError{Op: "create", Path: f.name, Err: fs.ErrExist}
+
+		return fmt.Errorf("%w; use -force to overwrite it", &err)
+	}
+
+	return nil
+}
+
+func (f *outputFlags) options() []abcrypt.Option {
+	var opts []abcrypt.Option
+
+	if f.force {
+		opts = append(opts, abcrypt.WithOverwrite())
+	}
+
+	if f.verify {
+		opts = append(opts, abcrypt.WithVerify())
+	}
+
+	return opts
+}
+
+// writeStdout calls fn with a buffered writer to the standard output.
+func (a *app) writeStdout(fn func(w io.Writer) error) error {
+	w := bufio.NewWriter(a.stdout)
+	if err := fn(w); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var packCommand = &command{
+	name:    "pack",
+	args:    "[OPTIONS] <DIR> [FILE]",
+	summary: "Encrypt a directory tree as a single archive",
+	run:     runPack,
+}
+
+func runPack(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	var output outputFlags
+
+	fs.BoolVar(&output.force, "force", false, "Overwrite the output file if it exists")
+	fs.BoolVar(&output.verify, "verify", false, "Read back the output file and check it before renaming it into place")
+	kdf := addKDFFlags(fs)
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 1, 2); err != nil {
+		return err
+	}
+
+	dir := fs.Arg(0)
+	output.name = fs.Arg(1)
+
+	opts, err := kdf.options()
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	if err := output.check(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%v is not a directory", dir)
+	}
+
+	passphrase, keyOpts, err := a.credentials(ctx, key, true)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, keyOpts...)
+
+	if !isStdio(output.name) {
+		return abcrypt.PackFile(ctx, output.name, dir, passphrase, append(opts, output.options()...)...)
+	}
+
+	return a.writeStdout(func(out io.Writer) error {
+		w, err := abcrypt.NewArchiveWriter(ctx, out, passphrase, opts...)
+		if err != nil {
+			return err
+		}
+
+		if err := w.AddDir(dir); err != nil {
+			return err
+		}
+
+		return w.Close()
+	})
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+)
+
+func TestPackUnpack(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeTree(t, src, "a.txt", "sub/b.txt", "sub/deep/c.txt")
+
+	archive := filepath.Join(t.TempDir(), "archive.abcrypt")
+
+	args := append([]string{"pack", "-verify"}, fastParams...)
+	if r := runApp(t, nil, []string{"passphrase", "passphrase"}, append(args, src, archive)...); r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	// The archive exists, so the command fails before prompting.
+	if r := runApp(t, nil, nil, append(args, src, archive)...); r.code != cli.ExitIO {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitIO, r.code)
+	}
+
+	ciphertext, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(ciphertext), "deep") {
+		t.Error("expected the names of the files to be encrypted")
+	}
+
+	parent := t.TempDir()
+	dst := filepath.Join(parent, "dst")
+
+	r := runApp(t, ciphertext, []string{"passphrase"}, "unpack", "-", dst)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/deep/c.txt"} {
+		b, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Error(err)
+
+			continue
+		}
+
+		if string(b) != name {
+			t.Errorf("expected contents `%v`, got `%s`", name, b)
+		}
+	}
+
+	if r := runApp(t, ciphertext, nil, "unpack", "-", dst); r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	// A modified archive leaves no directory.
+	ciphertext[len(ciphertext)-1] ^= 1
+	dst = filepath.Join(parent, "modified")
+
+	if r := runApp(t, ciphertext, []string{"passphrase"}, "unpack", "-", dst); r.code != cli.ExitCorrupted {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitCorrupted, r.code)
+	}
+
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected only the first directory, got `%v`", entries)
+	}
+}
+
+func TestPackToStdout(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeTree(t, src, "a.txt")
+
+	r := runApp(t, nil, []string{"passphrase", "passphrase"}, append(append([]string{"pack"}, fastParams...), src)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	if r := runApp(t, r.stdout, []string{"passphrase"}, "unpack", "-", dst); r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if b, err := os.ReadFile(filepath.Join(dst, "a.txt")); err != nil || string(b) != "a.txt" {
+		t.Errorf("unexpected contents `%s`: %v", b, err)
+	}
+
+	if r := runApp(t, nil, nil, "pack", filepath.Join(src, "a.txt")); r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/agent"
+	"github.com/sorairolake/abcrypt-go/askpass"
+	"golang.org/x/term"
+)
+
+var (
+	errNoTerminal         = errors.New("cannot prompt for the passphrase: no terminal is available; use -passphrase-from-env, -passphrase-from-file, -passphrase-from-fd, -askpass or -pinentry")
+	errPassphraseMismatch = errors.New("passphrases do not match")
+)
+
+// keyFlags represents the flags for the passphrase and the keyfile.
+type keyFlags struct {
+	prefix string
+	prompt string
+
+	keyfile  string
+	fromEnv  string
+	fromFile string
+	fromFD   int
+	askpass  string
+	pinentry string
+
+	// noAgent disables abcrypt-agent, such as for the new passphrase of
+	// rekey.
+	noAgent bool
+}
+
+// addKeyFlags adds the flags for the passphrase and the keyfile. The names of
+// the flags are prefixed with prefix, and prompt is shown when prompting for
+// the passphrase.
+func addKeyFlags(fs *flag.FlagSet, prefix, prompt string) *keyFlags {
+	f := keyFlags{prefix: prefix, prompt: prompt, fromFD: -1}
+
+	fs.StringVar(&f.keyfile, prefix+"keyfile", "", "Require the contents of a keyfile in addition to the passphrase")
+	fs.StringVar(&f.fromEnv, prefix+"passphrase-from-env", "", "Read the passphrase from the environment variable")
+	fs.StringVar(&f.fromFile, prefix+"passphrase-from-file", "", "Read the passphrase from the first line of the file")
+	fs.IntVar(&f.fromFD, prefix+"passphrase-from-fd", -1, "Read the passphrase from the first line of the file descriptor")
+	fs.StringVar(&f.askpass, prefix+"askpass", "", "Prompt for the passphrase with the askpass program")
+	fs.StringVar(&f.pinentry, prefix+"pinentry", "", "Prompt for the passphrase with the pinentry program")
+
+	return &f
+}
+
+// check reports an error if more than one passphrase source is specified.
+func (f *keyFlags) check() error {
+	n := 0
+
+	for _, set := range []bool{f.fromEnv != "", f.fromFile != "", f.fromFD >= 0, f.askpass != "", f.pinentry != ""} {
+		if set {
+			n++
+		}
+	}
+
+	if n > 1 {
+		return &usageError{err: fmt.Errorf("-%vpassphrase-from-env, -%[1]vpassphrase-from-file, -%[1]vpassphrase-from-fd, -%[1]vaskpass and -%[1]vpinentry are mutually exclusive", f.prefix)}
+	}
+
+	return nil
+}
+
+// credentials returns the passphrase and the options for the key derivation.
+//
+// The passphrase is read from the source specified by the flags. Otherwise,
+// if ABCRYPT_AGENT_SOCK is set and no keyfile is specified, the passphrase
+// held by abcrypt-agent is used. Otherwise, the passphrase is prompted for,
+// twice if confirm is true, with the program specified by ABCRYPT_ASKPASS or
+// ABCRYPT_PINENTRY, or on the terminal. If no terminal is available,
+// SSH_ASKPASS is used as a fallback.
+func (a *app) credentials(ctx context.Context, f *keyFlags, confirm bool) ([]byte, []abcrypt.Option, error) {
+	if err := f.check(); err != nil {
+		return nil, nil, err
+	}
+
+	var opts []abcrypt.Option
+
+	if f.keyfile != "" {
+		keyfile, err := os.ReadFile(f.keyfile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		opts = append(opts, abcrypt.WithKeyfile(keyfile))
+	}
+
+	var (
+		passphrase []byte
+		err        error
+	)
+
+	switch {
+	case f.fromEnv != "":
+		v, ok := os.LookupEnv(f.fromEnv)
+		if !ok {
+			return nil, nil, fmt.Errorf("environment variable %v is not set", f.fromEnv)
+		}
+
+		passphrase = []byte(v)
+	case f.fromFile != "":
+		passphrase, err = readPassphraseFile(f.fromFile)
+	case f.fromFD >= 0:
+		passphrase, err = readPassphraseFD(f.fromFD)
+	case f.askpass != "":
+		passphrase, err = promptPassphrase(askWith(ctx, &askpass.Program{Path: f.askpass}), f.prompt, confirm)
+	case f.pinentry != "":
+		passphrase, err = promptPassphrase(askWith(ctx, &askpass.Pinentry{Path: f.pinentry}), f.prompt, confirm)
+	default:
+		if client, err := agent.NewClientFromEnv(); err == nil && f.keyfile == "" && !f.noAgent {
+			return nil, append(opts, abcrypt.WithKeyDeriver(client)), nil
+		}
+
+		passphrase, err = promptPassphrase(a.ask(ctx), f.prompt, confirm)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return passphrase, opts, nil
+}
+
+// ask returns the function which prompts for the passphrase when no source is
+// specified by the flags.
+func (a *app) ask(ctx context.Context) func(prompt string) ([]byte, error) {
+	if asker, ok := askpass.FromEnv(false); ok {
+		return askWith(ctx, asker)
+	}
+
+	return func(prompt string) ([]byte, error) {
+		passphrase, err := a.prompt(prompt)
+		if errors.Is(err, errNoTerminal) {
+			if asker, ok := askpass.FromEnv(true); ok {
+				return asker.Ask(ctx, prompt)
+			}
+		}
+
+		return passphrase, err
+	}
+}
+
+func askWith(ctx context.Context, asker askpass.Asker) func(prompt string) ([]byte, error) {
+	return func(prompt string) ([]byte, error) {
+		return asker.Ask(ctx, prompt)
+	}
+}
+
+func promptPassphrase(ask func(prompt string) ([]byte, error), prompt string, confirm bool) ([]byte, error) {
+	passphrase, err := ask(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !confirm {
+		return passphrase, nil
+	}
+
+	confirmation, err := ask("Confirm passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	defer clear(confirmation)
+
+	if subtle.ConstantTimeCompare(passphrase, confirmation) != 1 {
+		return nil, errPassphraseMismatch
+	}
+
+	return passphrase, nil
+}
+
+func readPassphraseFile(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readFirstLine(f)
+}
+
+func readPassphraseFD(fd int) ([]byte, error) {
+	f := os.NewFile(uintptr(fd), "fd "+strconv.Itoa(fd))
+	if f == nil {
+		return nil, fmt.Errorf("invalid file descriptor %v", fd)
+	}
+	defer f.Close()
+
+	return readFirstLine(f)
+}
+
+// readFirstLine returns the first line of r without the line terminator. r is
+// read a byte at a time, so the rest of it is left unread and a writer which
+// keeps it open does not block.
+func readFirstLine(r io.Reader) ([]byte, error) {
+	var (
+		line []byte
+		b    [1]byte
+	)
+
+	for {
+		n, err := r.Read(b[:])
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+
+			line = append(line, b[0])
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			clear(line)
+
+			return nil, err
+		}
+	}
+
+	return bytes.TrimSuffix(line, []byte("\r")), nil
+}
+
+// promptTerminal prompts for the passphrase on the controlling terminal, so
+// the standard input can be used for the data.
+func promptTerminal(prompt string) ([]byte, error) {
+	in, out, err := openTTY()
+	if err != nil {
+		// Fall back to the standard input if it is a terminal.
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, errNoTerminal
+		}
+
+		in, out = os.Stdin, os.Stderr
+	} else {
+		defer closeTTY(in, out)
+	}
+
+	fmt.Fprint(out, prompt)
+	defer fmt.Fprintln(out)
+
+	return term.ReadPassword(int(in.Fd()))
+}
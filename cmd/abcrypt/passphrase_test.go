@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+func TestEncryptConfirmation(t *testing.T) {
+	t.Parallel()
+
+	r := runApp(t, []byte(data), []string{"passphrase", "passphrase2"}, append([]string{"encrypt"}, fastParams...)...)
+	if r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	if !strings.Contains(r.stderr, errPassphraseMismatch.Error()) {
+		t.Errorf("expected error `%v`, got `%v`", errPassphraseMismatch, r.stderr)
+	}
+}
+
+func TestPassphraseFromEnv(t *testing.T) {
+	t.Setenv("ABCRYPT_TEST_PASSPHRASE", "passphrase")
+
+	r := runApp(t, []byte(data), nil, append([]string{"encrypt", "-passphrase-from-env", "ABCRYPT_TEST_PASSPHRASE"}, fastParams...)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if _, err := abcrypt.Decrypt(r.stdout, []byte("passphrase")); err != nil {
+		t.Error(err)
+	}
+
+	if r := runApp(t, r.stdout, nil, "decrypt", "-passphrase-from-env", "ABCRYPT_TEST_UNSET"); r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+}
+
+func TestPassphraseFromFile(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(name, []byte("passphrase\r\nignored\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext := encryptData(t, "passphrase")
+
+	r := runApp(t, ciphertext, nil, "decrypt", "-passphrase-from-file", name)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if string(r.stdout) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, r.stdout)
+	}
+}
+
+func TestPassphraseSourcesExclusive(t *testing.T) {
+	t.Parallel()
+
+	r := runApp(t, []byte(data), nil, "encrypt", "-passphrase-from-env", "A", "-passphrase-from-file", "B")
+	if r.code != exitUsage {
+		t.Errorf("expected exit code `%v`, got `%v`", exitUsage, r.code)
+	}
+}
+
+func TestReadFirstLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"passphrase", "passphrase"},
+		{"passphrase\n", "passphrase"},
+		{"passphrase\r\n", "passphrase"},
+		{"pass phrase \nsecond\n", "pass phrase "},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		line, err := readFirstLine(strings.NewReader(tt.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(line) != tt.expected {
+			t.Errorf("expected line `%q`, got `%q`", tt.expected, line)
+		}
+	}
+
+	r := strings.NewReader("passphrase\nsecond\n")
+	if _, err := readFirstLine(r); err != nil {
+		t.Fatal(err)
+	}
+
+	if rest, _ := io.ReadAll(r); string(rest) != "second\n" {
+		t.Errorf("expected the rest `%q`, got `%q`", "second\n", rest)
+	}
+
+	if _, err := readFirstLine(errReader{}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("error")
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build unix
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/askpass"
+)
+
+// writeScript writes a shell script to a temporary directory and returns its
+// path.
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "helper")
+	if err := os.WriteFile(name, []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestPassphraseFromFD(t *testing.T) {
+	t.Parallel()
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer pr.Close()
+	defer pw.Close()
+
+	// The writer is kept open, so the command must not wait for the end of
+	// the file.
+	if _, err := pw.WriteString("passphrase\nrest"); err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext := encryptData(t, "passphrase")
+
+	// The command closes the file descriptor after reading it, so pass a
+	// duplicate of it.
+	fd, err := syscall.Dup(int(pr.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := runApp(t, ciphertext, nil, "decrypt", "-passphrase-from-fd", strconv.Itoa(fd))
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if string(r.stdout) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, r.stdout)
+	}
+
+	if err := pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first line is consumed.
+	if rest, _ := io.ReadAll(pr); string(rest) != "rest" {
+		t.Errorf("expected the rest `%v`, got `%s`", "rest", rest)
+	}
+}
+
+func TestPassphraseFromAskpass(t *testing.T) {
+	t.Parallel()
+
+	log := filepath.Join(t.TempDir(), "log")
+	path := writeScript(t, `printf '%s\n' "$1" >> "`+log+`"
+printf 'passphrase\n'
+`)
+
+	r := runApp(t, []byte(data), nil, append([]string{"encrypt", "-askpass", path}, fastParams...)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if _, err := abcrypt.Decrypt(r.stdout, []byte("passphrase")); err != nil {
+		t.Error(err)
+	}
+
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "Enter passphrase: \nConfirm passphrase: \n"; string(b) != expected {
+		t.Errorf("expected prompts `%q`, got `%q`", expected, b)
+	}
+}
+
+func TestPassphraseFromPinentry(t *testing.T) {
+	t.Setenv(askpass.EnvAskpass, "")
+	t.Setenv(askpass.EnvPinentry, writeScript(t, `echo "OK Pleased to meet you"
+while read -r cmd rest; do
+	case "$cmd" in
+	GETPIN) echo "D passphrase"; echo "OK" ;;
+	BYE) echo "OK"; exit 0 ;;
+	*) echo "OK" ;;
+	esac
+done
+`))
+
+	ciphertext := encryptData(t, "passphrase")
+
+	r := runApp(t, ciphertext, nil, "decrypt")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if string(r.stdout) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, r.stdout)
+	}
+}
+
+func TestSSHAskpassFallback(t *testing.T) {
+	t.Setenv(askpass.EnvAskpass, "")
+	t.Setenv(askpass.EnvPinentry, "")
+	t.Setenv(askpass.EnvSSHAskpass, writeScript(t, "printf 'passphrase\\n'\n"))
+
+	ciphertext := encryptData(t, "passphrase")
+
+	var stdout, stderr bytes.Buffer
+
+	a := app{
+		stdin:  bytes.NewReader(ciphertext),
+		stdout: &stdout,
+		stderr: &stderr,
+		prompt: func(string) ([]byte, error) {
+			return nil, errNoTerminal
+		},
+	}
+
+	if code := a.run(context.Background(), []string{"decrypt"}); code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, code, stderr.String())
+	}
+
+	if s := stdout.String(); s != data {
+		t.Errorf("expected plaintext `%v`, got `%v`", data, s)
+	}
+}
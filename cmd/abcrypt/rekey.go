@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var rekeyCommand = &command{
+	name:    "rekey",
+	args:    "[OPTIONS] [FILE]",
+	summary: "Re-encrypt a file with a new passphrase or new Argon2 parameters",
+	run:     runRekey,
+}
+
+func runRekey(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	output := addOutputFlags(fs)
+	kdf := addKDFFlags(fs)
+	key := addKeyFlags(fs, "", "Enter current passphrase: ")
+	newKey := addKeyFlags(fs, "new-", "Enter new passphrase: ")
+	newKey.noAgent = true
+
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	if err := output.check(); err != nil {
+		return err
+	}
+
+	ciphertext, err := a.readInput(inputName(fs))
+	if err != nil {
+		return err
+	}
+
+	header, err := checkHeader(ciphertext)
+	if err != nil {
+		return err
+	}
+
+	// Keep the Argon2 type and the Argon2 parameters of the file unless
+	// any of them is specified.
+	if !kdf.visited(fs) {
+		kdf.argon2Type = header.Argon2Type
+		kdf.memoryCost = uint(header.MemoryCost)
+		kdf.timeCost = uint(header.TimeCost)
+		kdf.parallelism = uint(header.Parallelism)
+	}
+
+	encOpts, err := kdf.options()
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := abcrypt.DecryptWithOptions(ctx, ciphertext, passphrase, opts...)
+	if err != nil {
+		return err
+	}
+	defer clear(plaintext)
+
+	newPassphrase, newOpts, err := a.credentials(ctx, newKey, true)
+	if err != nil {
+		return err
+	}
+
+	opts = append(encOpts, newOpts...)
+
+	if !isStdio(output.name) {
+		return abcrypt.EncryptFile(ctx, output.name, bytes.NewReader(plaintext), newPassphrase, append(opts, output.options()...)...)
+	}
+
+	ciphertext, err = abcrypt.EncryptWithOptions(ctx, plaintext, newPassphrase, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.stdout.Write(ciphertext)
+
+	return err
+}
@@ -0,0 +1,406 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// defaultMemoryBudget is the default total memory in KiB of the concurrent key
+// derivations, which is 1 GiB.
+const defaultMemoryBudget = 1 << 20
+
+// globsFlag represents a flag which may be repeated to give glob patterns.
+type globsFlag []string
+
+func (g *globsFlag) String() string {
+	return strings.Join(*g, ",")
+}
+
+func (g *globsFlag) Set(s string) error {
+	if _, err := path.Match(s, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern %q", s)
+	}
+
+	*g = append(*g, s)
+
+	return nil
+}
+
+// treeFlags represents the flags for processing the files in a directory tree.
+type treeFlags struct {
+	recursive    bool
+	include      globsFlag
+	exclude      globsFlag
+	memoryBudget uint
+}
+
+func addTreeFlags(fs *flag.FlagSet) *treeFlags {
+	var f treeFlags
+
+	fs.BoolVar(&f.recursive, "r", false, "Process the files in the directory tree given as the argument")
+	fs.Var(&f.include, "include", "Process only the files matching the glob pattern (with -r; may be repeated)")
+	fs.Var(&f.exclude, "exclude", "Skip the files and directories matching the glob pattern (with -r; may be repeated)")
+	fs.UintVar(&f.memoryBudget, "memory-budget", defaultMemoryBudget, "Limit the total memory in KiB of the concurrent key derivations (with -r)")
+
+	return &f
+}
+
+// check reports a usage error if the flags for a directory tree are given
+// without -r, or -r is given without a directory.
+func (f *treeFlags) check(fs *flag.FlagSet) error {
+	if !f.recursive {
+		var name string
+
+		fs.Visit(func(fl *flag.Flag) {
+			switch fl.Name {
+			case "include", "exclude", "memory-budget":
+				name = fl.Name
+			}
+		})
+
+		if name != "" {
+			return &usageError{err: fmt.Errorf("-%v requires -r", name)}
+		}
+
+		return nil
+	}
+
+	if fs.NArg() != 1 || isStdio(fs.Arg(0)) {
+		return &usageError{err: errors.New("-r requires a directory")}
+	}
+
+	if f.memoryBudget == 0 {
+		return &usageError{err: errors.New("-memory-budget must be greater than 0")}
+	}
+
+	return nil
+}
+
+// selected reports whether the file is processed. rel is the slash-separated
+// path relative to the root, and the patterns are matched against both it and
+// its last element.
+func (f *treeFlags) selected(rel string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, rel) {
+		return false
+	}
+
+	return !matchAny(f.exclude, rel)
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, rel); ok {
+			return true
+		}
+
+		if ok, _ := path.Match(p, path.Base(rel)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// treeJob represents a file in the directory tree to be processed.
+type treeJob struct {
+	input  string
+	output string
+
+	// memoryCost represents the memory cost in KiB of the key derivation.
+	memoryCost uint64
+}
+
+// treeSummary represents the results of processing a directory tree.
+type treeSummary struct {
+	mu sync.Mutex
+
+	// verb is the past tense of the operation, such as "Encrypted".
+	verb string
+
+	done    int
+	skipped int
+	failed  int
+}
+
+// walkTree collects the files in the directory tree. When encrypting, the
+// output file has the abcrypt file extension, and the files which already
+// have it are skipped. When decrypting, only the files which have the
+// extension are processed, and it is removed from the output file. If outDir
+// is not empty, the output files are written to the same relative paths in
+// outDir instead of next to the input files.
+func (f *treeFlags) walkTree(root, outDir string, decrypt bool, s *treeSummary) ([]treeJob, error) {
+	var jobs []treeJob
+
+	err := filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && matchAny(f.exclude, rel) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() || !f.selected(rel) {
+			return nil
+		}
+
+		encrypted := strings.HasSuffix(name, abcrypt.FileExtension)
+		if encrypted != decrypt {
+			s.skipped++
+
+			return nil
+		}
+
+		output := name
+		if outDir != "" {
+			output = filepath.Join(outDir, filepath.FromSlash(rel))
+		}
+
+		if decrypt {
+			output = strings.TrimSuffix(output, abcrypt.FileExtension)
+		} else {
+			output += abcrypt.FileExtension
+		}
+
+		jobs = append(jobs, treeJob{input: name, output: output})
+
+		return nil
+	})
+
+	return jobs, err
+}
+
+// runTree processes the jobs with a pool of workers, and reports the failed
+// files and the summary to the standard error.
+//
+// The number of workers is determined by the memory budget rather than the
+// number of CPUs, since each job runs Argon2 with its memory cost. The key
+// derivations acquire their memory from the budget, so the total memory does
+// not exceed it even if the memory costs of the files differ.
+func (a *app) runTree(ctx context.Context, f *treeFlags, jobs []treeJob, s *treeSummary, process func(ctx context.Context, j treeJob, opts ...abcrypt.Option) error) error {
+	budget := abcrypt.NewMemoryBudget(uint64(f.memoryBudget))
+
+	minCost := uint64(f.memoryBudget)
+	for _, j := range jobs {
+		minCost = min(minCost, max(j.memoryCost, 1))
+	}
+
+	workers := min(max(uint64(f.memoryBudget)/minCost, 1), uint64(len(jobs)))
+
+	ch := make(chan treeJob)
+
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range ch {
+				err := ctx.Err()
+				if err == nil {
+					err = process(ctx, j, abcrypt.WithMemoryBudget(budget))
+				}
+
+				s.record(a, j.input, err)
+			}
+		}()
+	}
+
+	for _, j := range jobs {
+		ch <- j
+	}
+
+	close(ch)
+	wg.Wait()
+
+	return s.report(a)
+}
+
+// record records the result of a file, and reports it if it failed.
+func (s *treeSummary) record(a *app, name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.done++
+
+		return
+	}
+
+	s.failed++
+
+	_ = writeFileError(a.stderr, a.jsonErrors, name, err)
+}
+
+// report prints the summary, and returns an error if any file failed.
+func (s *treeSummary) report(a *app) error {
+	total := s.done + s.failed
+
+	fmt.Fprintf(a.stderr, "%v %v of %v files (%v skipped, %v failed)\n", s.verb, s.done, total, s.skipped, s.failed)
+
+	if s.failed > 0 {
+		return fmt.Errorf("could not process %v of %v files", s.failed, total)
+	}
+
+	return nil
+}
+
+// encryptTree encrypts the files in the directory tree.
+func (a *app) encryptTree(ctx context.Context, f *treeFlags, output *outputFlags, key *keyFlags, kdf *kdfFlags, root string) error {
+	opts, err := kdf.options()
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	s := treeSummary{verb: "Encrypted"}
+
+	jobs, err := f.walkTree(root, output.name, false, &s)
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		jobs[i].memoryCost = uint64(kdf.memoryCost)
+	}
+
+	if len(jobs) == 0 {
+		return s.report(a)
+	}
+
+	passphrase, keyOpts, err := a.credentials(ctx, key, true)
+	if err != nil {
+		return err
+	}
+
+	opts = append(append(opts, keyOpts...), output.options()...)
+
+	return a.runTree(ctx, f, jobs, &s, func(ctx context.Context, j treeJob, extra ...abcrypt.Option) error {
+		in, err := os.Open(j.input)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		if err := os.MkdirAll(filepath.Dir(j.output), 0o755); err != nil {
+			return err
+		}
+
+		return abcrypt.EncryptFile(ctx, j.output, in, passphrase, slices.Concat(opts, extra)...)
+	})
+}
+
+// decryptTree decrypts the files in the directory tree. The headers are read
+// before prompting for the passphrase, so that the files which cannot be
+// decrypted are reported without waiting for the key derivation.
+func (a *app) decryptTree(ctx context.Context, f *treeFlags, output *outputFlags, key *keyFlags, root string) error {
+	s := treeSummary{verb: "Decrypted"}
+
+	jobs, err := f.walkTree(root, output.name, true, &s)
+	if err != nil {
+		return err
+	}
+
+	valid := jobs[:0]
+
+	for _, j := range jobs {
+		header, err := readHeader(j.input)
+		if err != nil {
+			s.record(a, j.input, err)
+
+			continue
+		}
+
+		j.memoryCost = uint64(header.MemoryCost)
+		valid = append(valid, j)
+	}
+
+	jobs = valid
+
+	if len(jobs) == 0 {
+		return s.report(a)
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, output.options()...)
+
+	return a.runTree(ctx, f, jobs, &s, func(ctx context.Context, j treeJob, extra ...abcrypt.Option) error {
+		in, err := os.Open(j.input)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		if err := os.MkdirAll(filepath.Dir(j.output), 0o755); err != nil {
+			return err
+		}
+
+		return abcrypt.DecryptFile(ctx, j.output, in, passphrase, slices.Concat(opts, extra)...)
+	})
+}
+
+// readHeader reads the header of the file, and checks that it can be
+// decrypted.
+func readHeader(name string) (*abcrypt.Header, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, abcrypt.HeaderSize+abcrypt.TagSize)
+
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return checkHeader(buf[:n])
+}
+
+// checkHeader parses the header of the encrypted data, and checks that it can
+// be decrypted.
+func checkHeader(ciphertext []byte) (*abcrypt.Header, error) {
+	header, err := abcrypt.NewHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := header.CheckKDF(); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates the files in dir with their names as the contents.
+func writeTree(t *testing.T, dir string, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEncryptDecryptTree(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTree(t, dir, "a.txt", "b.md", "sub/c.txt", "sub/deep/d.txt", "cache/e.txt", "old.abcrypt")
+
+	// A single prompt is shared by all the files.
+	args := append([]string{"encrypt", "-r", "-exclude", "cache", "-exclude", "*.md", "-memory-budget", "64"}, fastParams...)
+
+	r := runApp(t, nil, []string{"passphrase", "passphrase"}, append(args, dir)...)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if expected := "Encrypted 3 of 3 files (1 skipped, 0 failed)"; !strings.Contains(r.stderr, expected) {
+		t.Errorf("expected summary `%v`, got `%v`", expected, r.stderr)
+	}
+
+	for _, name := range []string{"a.txt", "sub/c.txt", "sub/deep/d.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)+".abcrypt")); err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, name := range []string{"b.md", "cache/e.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)+".abcrypt")); err == nil {
+			t.Errorf("expected `%v` not to be encrypted", name)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+
+	r = runApp(t, nil, []string{"passphrase"}, "decrypt", "-r", "-include", "sub/*", "-include", "sub/*/*", "-include", "a.*", "-output", out, dir)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	for _, name := range []string{"a.txt", "sub/c.txt", "sub/deep/d.txt"} {
+		b, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(name)))
+		if err != nil {
+			t.Error(err)
+
+			continue
+		}
+
+		if string(b) != name {
+			t.Errorf("expected contents `%v`, got `%s`", name, b)
+		}
+	}
+}
+
+func TestDecryptTreeWithFailures(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTree(t, dir, "a.txt", "invalid.abcrypt")
+
+	if err := os.WriteFile(filepath.Join(dir, "b.txt.abcrypt"), encryptData(t, "password"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := append([]string{"encrypt", "-r"}, fastParams...)
+	if r := runApp(t, nil, []string{"passphrase", "passphrase"}, append(args, dir)...); r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	r := runApp(t, nil, []string{"passphrase"}, "decrypt", "-r", "-output", t.TempDir(), dir)
+	if r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	for _, s := range []string{
+		"invalid.abcrypt: abcrypt: encrypted data is shorter than 164 bytes",
+		"b.txt.abcrypt: abcrypt: invalid header MAC",
+		"Decrypted 1 of 3 files (1 skipped, 2 failed)",
+	} {
+		if !strings.Contains(r.stderr, s) {
+			t.Errorf("expected error output to contain `%v`, got `%v`", s, r.stderr)
+		}
+	}
+}
+
+func TestEncryptTreeOverMemoryBudget(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTree(t, dir, "a.txt")
+
+	args := append([]string{"encrypt", "-r", "-memory-budget", "16"}, fastParams...)
+
+	r := runApp(t, nil, []string{"passphrase", "passphrase"}, append(args, dir)...)
+	if r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	if !strings.Contains(r.stderr, "Encrypted 0 of 1 files (0 skipped, 1 failed)") {
+		t.Errorf("unexpected error output `%v`", r.stderr)
+	}
+}
+
+func TestTreeUsage(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"encrypt", "-include", "*.txt"},
+		{"encrypt", "-r"},
+		{"encrypt", "-r", "-"},
+		{"decrypt", "-r", "-memory-budget", "0", "dir"},
+		{"decrypt", "-r", "-include", "[", "dir"},
+	} {
+		if r := runApp(t, nil, nil, args...); r.code != exitUsage {
+			t.Errorf("expected exit code of `%v` `%v`, got `%v`", args, exitUsage, r.code)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build !windows
+
+package main
+
+import "os"
+
+func openTTY() (*os.File, *os.File, error) {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, f, nil
+}
+
+func closeTTY(in, _ *os.File) {
+	_ = in.Close()
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build windows
+
+package main
+
+import "os"
+
+func openTTY() (*os.File, *os.File, error) {
+	in, err := os.OpenFile("CONIN$", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+	if err != nil {
+		_ = in.Close()
+
+		return nil, nil, err
+	}
+
+	return in, out, nil
+}
+
+func closeTTY(in, out *os.File) {
+	_ = in.Close()
+	_ = out.Close()
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var unpackCommand = &command{
+	name:    "unpack",
+	args:    "[OPTIONS] <FILE> <DIR>",
+	summary: "Decrypt an archive created by pack into a new directory",
+	run:     runUnpack,
+}
+
+func runUnpack(ctx context.Context, a *app, fs *flag.FlagSet, args []string) (err error) {
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	dir := filepath.Clean(fs.Arg(1))
+
+	if _, err := os.Lstat(dir); err == nil {
+		return fmt.Errorf("%v already exists", dir)
+	}
+
+	f, err := a.openInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	in := bufio.NewReader(f)
+
+	// Reject a file which cannot be decrypted before prompting.
+	header, _ := in.Peek(abcrypt.HeaderSize + abcrypt.TagSize)
+	if _, err := checkHeader(header); err != nil {
+		return err
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return err
+	}
+
+	r, err := abcrypt.NewArchiveReader(ctx, in, passphrase, opts...)
+	if err != nil {
+		return err
+	}
+
+	// The archive is extracted into a temporary directory which is renamed
+	// only after the MAC of the ciphertext has been verified, so no
+	// unauthenticated file is left.
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(tmp)
+		}
+	}()
+
+	if err := r.Extract(tmp); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, dir)
+}
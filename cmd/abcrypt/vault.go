@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sorairolake/abcrypt-go/vault"
+)
+
+const (
+	vaultArgs    = "<COMMAND> [OPTIONS] <FILE> [NAME]..."
+	vaultSummary = "Manage the named secrets in an encrypted vault file"
+)
+
+var vaultCommand = &command{
+	name:    "vault",
+	args:    vaultArgs,
+	summary: vaultSummary,
+	run:     runVault,
+}
+
+var vaultCommands = []*command{
+	{
+		name:    "vault get",
+		args:    "[OPTIONS] <FILE> <NAME>",
+		summary: "Print the value of a secret",
+		run:     runVaultGet,
+	},
+	{
+		name:    "vault set",
+		args:    "[OPTIONS] <FILE> <NAME>",
+		summary: "Set the value of a secret to the standard input, creating the vault if it does not exist",
+		run:     runVaultSet,
+	},
+	{
+		name:    "vault ls",
+		args:    "[OPTIONS] <FILE>",
+		summary: "List the names of the secrets",
+		run:     runVaultList,
+	},
+	{
+		name:    "vault rm",
+		args:    "[OPTIONS] <FILE> <NAME>...",
+		summary: "Remove secrets",
+		run:     runVaultRemove,
+	},
+}
+
+func runVault(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	fs.Usage = func() {
+		w := fs.Output()
+
+		fmt.Fprintf(w, "Usage: %v %v\n", fs.Name(), vaultArgs)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, vaultSummary+".")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Commands:")
+
+		for _, cmd := range vaultCommands {
+			fmt.Fprintf(w, "  %-6s %v\n", cmd.name[len("vault "):], cmd.summary)
+		}
+	}
+
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+
+	for _, cmd := range vaultCommands {
+		if cmd.name == "vault "+name {
+			return cmd.run(ctx, a, a.newFlagSet(cmd), fs.Args()[1:])
+		}
+	}
+
+	fs.Usage()
+
+	return &usageError{err: fmt.Errorf("unknown vault command %q", name), reported: true}
+}
+
+// openVault opens the vault given as the first positional argument.
+func (a *app) openVault(ctx context.Context, fs *flag.FlagSet, key *keyFlags) (*vault.Vault, error) {
+	// Reject a file which cannot be decrypted before prompting.
+	if _, err := readHeader(fs.Arg(0)); err != nil {
+		return nil, err
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(passphrase)
+
+	return vault.Open(ctx, fs.Arg(0), passphrase, opts...)
+}
+
+func runVaultGet(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	v, err := a.openVault(ctx, fs, key)
+	if err != nil {
+		return err
+	}
+	defer v.Close()
+
+	value, err := v.Get(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer clear(value)
+
+	_, err = a.stdout.Write(value)
+
+	return err
+}
+
+func runVaultSet(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	kdf := addKDFFlags(fs)
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+
+	// The Argon2 type and the Argon2 parameters are used only for a new
+	// vault, and the passphrase is confirmed for it.
+	_, err := readHeader(name)
+	create := errors.Is(err, os.ErrNotExist)
+
+	if err != nil && !create {
+		return err
+	}
+
+	encOpts, err := kdf.options()
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	value, err := io.ReadAll(a.stdin)
+	if err != nil {
+		return err
+	}
+	defer clear(value)
+
+	passphrase, opts, err := a.credentials(ctx, key, create)
+	if err != nil {
+		return err
+	}
+	defer clear(passphrase)
+
+	var v *vault.Vault
+	if create {
+		v, err = vault.Create(ctx, name, passphrase, append(encOpts, opts...)...)
+	} else {
+		v, err = vault.Open(ctx, name, passphrase, opts...)
+	}
+
+	if err != nil {
+		return err
+	}
+	defer v.Close()
+
+	if err := v.Set(fs.Arg(1), value); err != nil {
+		return err
+	}
+
+	return v.Save(ctx)
+}
+
+func runVaultList(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	v, err := a.openVault(ctx, fs, key)
+	if err != nil {
+		return err
+	}
+	defer v.Close()
+
+	return a.writeStdout(func(w io.Writer) error {
+		for _, name := range v.List() {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func runVaultRemove(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 2, -1); err != nil {
+		return err
+	}
+
+	v, err := a.openVault(ctx, fs, key)
+	if err != nil {
+		return err
+	}
+	defer v.Close()
+
+	for _, name := range fs.Args()[1:] {
+		if err := v.Delete(name); err != nil {
+			return err
+		}
+	}
+
+	return v.Save(ctx)
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+)
+
+func TestVault(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "secrets.abcrypt")
+
+	// The passphrase is confirmed only when the vault is created.
+	args := append([]string{"vault", "set"}, fastParams...)
+	if r := runApp(t, []byte("s3cr3t"), []string{"passphrase", "passphrase"}, append(args, name, "db/password")...); r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	for _, s := range []string{"api-key", "token"} {
+		if r := runApp(t, []byte("value of "+s), []string{"passphrase"}, "vault", "set", name, s); r.code != exitSuccess {
+			t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+		}
+	}
+
+	r := runApp(t, nil, []string{"passphrase"}, "vault", "get", name, "db/password")
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if string(r.stdout) != "s3cr3t" {
+		t.Errorf("expected value `%v`, got `%s`", "s3cr3t", r.stdout)
+	}
+
+	if r := runApp(t, nil, []string{"passphrase"}, "vault", "rm", name, "token"); r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	r = runApp(t, nil, []string{"passphrase"}, "vault", "ls", name)
+	if r.code != exitSuccess {
+		t.Fatalf("expected exit code `%v`, got `%v`: %v", exitSuccess, r.code, r.stderr)
+	}
+
+	if expected := "api-key\ndb/password\n"; string(r.stdout) != expected {
+		t.Errorf("expected names `%v`, got `%s`", expected, r.stdout)
+	}
+
+	r = runApp(t, nil, []string{"passphrase"}, "vault", "get", name, "token")
+	if r.code != exitFailure {
+		t.Errorf("expected exit code `%v`, got `%v`", exitFailure, r.code)
+	}
+
+	if !strings.Contains(r.stderr, "vault: secret `token` not found") {
+		t.Errorf("unexpected error output `%v`", r.stderr)
+	}
+
+	if r := runApp(t, nil, []string{"password"}, "vault", "ls", name); r.code != cli.ExitInvalidPassphrase {
+		t.Errorf("expected exit code `%v`, got `%v`", cli.ExitInvalidPassphrase, r.code)
+	}
+}
+
+func TestVaultUsage(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"vault"},
+		{"vault", "unknown"},
+		{"vault", "get", "file"},
+		{"vault", "ls"},
+		{"vault", "rm", "file"},
+	} {
+		if r := runApp(t, nil, nil, args...); r.code != exitUsage {
+			t.Errorf("expected exit code of `%v` `%v`, got `%v`", args, exitUsage, r.code)
+		}
+	}
+
+	r := runApp(t, nil, nil, "help", "vault")
+	if r.code != exitSuccess {
+		t.Errorf("expected exit code `%v`, got `%v`", exitSuccess, r.code)
+	}
+
+	for _, s := range []string{"Usage: abcrypt vault <COMMAND>", "  get    Print the value of a secret"} {
+		if !strings.Contains(r.stderr, s) {
+			t.Errorf("expected usage to contain `%v`, got `%v`", s, r.stderr)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var verifyCommand = &command{
+	name:    "verify",
+	args:    "[OPTIONS] [FILE]",
+	summary: "Check that a file decrypts successfully without writing the plaintext",
+	run:     runVerify,
+}
+
+func runVerify(ctx context.Context, a *app, fs *flag.FlagSet, args []string) error {
+	key := addKeyFlags(fs, "", "Enter passphrase: ")
+
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	f, err := a.openInput(inputName(fs))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	in := bufio.NewReader(f)
+
+	header, _ := in.Peek(abcrypt.HeaderSize + abcrypt.TagSize)
+	if _, err := checkHeader(header); err != nil {
+		return err
+	}
+
+	passphrase, opts, err := a.credentials(ctx, key, false)
+	if err != nil {
+		return err
+	}
+
+	r, err := abcrypt.NewReader(ctx, in, passphrase, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(io.Discard, r)
+
+	return err
+}
@@ -5,10 +5,10 @@
 package abcrypt
 
 import (
-	"fmt"
-	"math"
+	"context"
+	"crypto/cipher"
+	"io"
 
-	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
@@ -16,70 +16,123 @@ import (
 type Decryptor struct {
 	header     *header
 	dk         *derivedKey
+	aead       cipher.AEAD
 	ciphertext []byte
+	observer   Observer
+	pepperID   string
 }
 
 // NewDecryptor creates a new [Decryptor].
 func NewDecryptor(ciphertext, passphrase []byte) (*Decryptor, error) {
+	return NewDecryptorWithOptions(context.Background(), ciphertext, passphrase)
+}
+
+// NewDecryptorWithOptions creates a new [Decryptor] with the given options.
+//
+// ctx is used while waiting for a [MemoryBudget].
+func NewDecryptorWithOptions(ctx context.Context, ciphertext, passphrase []byte, opts ...Option) (*Decryptor, error) {
 	header, err := parse(ciphertext)
 	if err != nil {
 		return nil, err
 	}
 
-	if header.argon2Version == version0x10 {
-		panic("abcrypt: version 0x10 is not supported")
-	}
+	o := newOptions(opts)
 
-	if header.parallelism > math.MaxUint8 {
-		msg := fmt.Sprintf("abcrypt: `parallelism` over %v is not supported", math.MaxUint8)
-		panic(msg)
+	derivedKey, pepperID, err := unlock(ctx, o, header, ciphertext, passphrase)
+	if err != nil {
+		return nil, err
 	}
 
-	s := header.salt[:]
-	t := header.timeCost
-	m := header.memoryCost
-	p := uint8(header.parallelism)
-
-	// The derived key size is 96 bytes. The first 256 bits are for
-	// XChaCha20-Poly1305 key, and the last 512 bits are for
-	// BLAKE2b-512-MAC key.
-	var k []byte
-
-	switch header.argon2Type {
-	case argon2d:
-		panic("abcrypt: Argon2d is not supported")
-	case Argon2i:
-		k = argon2.Key(passphrase, s, t, m, p, derivedKeySize)
-	case Argon2id:
-		k = argon2.IDKey(passphrase, s, t, m, p, derivedKeySize)
+	d, err := newDecryptor(o, header, derivedKey, ciphertext)
+	if err != nil {
+		return nil, err
 	}
 
-	derivedKey := newDerivedKey([derivedKeySize]byte(k))
+	d.pepperID = pepperID
 
+	return d, nil
+}
+
+func newDecryptor(o *options, header *header, derivedKey *derivedKey, ciphertext []byte) (*Decryptor, error) {
 	if err := header.verifyMAC(derivedKey.mac[:], ciphertext[84:HeaderSize]); err != nil {
+		o.observer.HeaderMACFailure()
+
 		return nil, err
 	}
 
-	d := Decryptor{header, derivedKey, ciphertext[HeaderSize:]}
+	aead, err := chacha20poly1305.NewX(derivedKey.encrypt[:])
+	if err != nil {
+		panic(err)
+	}
+
+	d := Decryptor{header, derivedKey, aead, ciphertext[HeaderSize:], o.observer, ""}
 
 	return &d, nil
 }
 
+// PepperID returns the ID of the pepper which authenticated the header, or an
+// empty string if no pepper was used.
+func (d *Decryptor) PepperID() string {
+	return d.pepperID
+}
+
 // Decrypt decrypts the ciphertext and returns the plaintext.
 func (d *Decryptor) Decrypt() ([]byte, error) {
-	cipher, err := chacha20poly1305.NewX(d.dk.encrypt[:])
+	return d.AppendDecrypt(nil)
+}
+
+// AppendDecrypt decrypts the ciphertext, appends the plaintext to dst and
+// returns the resulting slice.
+//
+// If dst has enough spare capacity for [Decryptor.OutLen] bytes, this does
+// not allocate. dst and the ciphertext must not overlap. If the MAC of the
+// ciphertext is invalid, the appended bytes are zeroed and dst is returned
+// unchanged along with the error.
+func (d *Decryptor) AppendDecrypt(dst []byte) ([]byte, error) {
+	out, err := d.aead.Open(dst, d.header.nonce[:], d.ciphertext, nil)
 	if err != nil {
-		panic(err)
+		d.observer.PayloadMACFailure()
+
+		return dst, &InvalidMACError{err}
 	}
 
-	plaintext, err := cipher.Open(nil, d.header.nonce[:], d.ciphertext, nil)
+	d.observer.BytesProcessed(OperationDecrypt, d.OutLen())
+
+	return out, nil
+}
+
+// DecryptInPlace decrypts the ciphertext in the buffer passed to
+// [NewDecryptor] and returns the plaintext, which shares the buffer starting
+// at offset [HeaderSize].
+//
+// This does not allocate. The ciphertext is overwritten, so the [Decryptor]
+// must not be used afterwards. If the MAC of the ciphertext is invalid, the
+// payload in the buffer is zeroed.
+func (d *Decryptor) DecryptInPlace() ([]byte, error) {
+	plaintext, err := d.AppendDecrypt(d.ciphertext[:0])
 	if err != nil {
-		return nil, &InvalidMACError{err}
+		return nil, err
 	}
 
 	return plaintext, nil
 }
 
+// WriteTo decrypts the ciphertext and writes the plaintext to w.
+//
+// Nothing is written if the MAC of the ciphertext is invalid.
+//
+// This implements [io.WriterTo].
+func (d *Decryptor) WriteTo(w io.Writer) (int64, error) {
+	plaintext, err := d.Decrypt()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(plaintext)
+
+	return int64(n), err
+}
+
 // OutLen returns the number of output bytes of the decrypted data.
 func (d *Decryptor) OutLen() int {
 	return len(d.ciphertext) - TagSize
@@ -97,3 +150,17 @@ func Decrypt(ciphertext, passphrase []byte) ([]byte, error) {
 
 	return cipher.Decrypt()
 }
+
+// DecryptWithOptions decrypts the ciphertext with the given options and
+// returns the plaintext.
+//
+// This is a convenience function for using [NewDecryptorWithOptions] and
+// [Decryptor.Decrypt].
+func DecryptWithOptions(ctx context.Context, ciphertext, passphrase []byte, opts ...Option) ([]byte, error) {
+	cipher, err := NewDecryptorWithOptions(ctx, ciphertext, passphrase, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.Decrypt()
+}
@@ -5,6 +5,9 @@
 package abcrypt_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"errors"
 	"os"
 	"slices"
@@ -183,6 +186,59 @@ func TestDecryptUnknownVersion(t *testing.T) {
 	}
 }
 
+func TestDecryptUnsupportedKDF(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		"testdata/v1/argon2d/v0x10/data.txt.abcrypt",
+		"testdata/v1/argon2d/v0x13/data.txt.abcrypt",
+		"testdata/v1/argon2i/v0x10/data.txt.abcrypt",
+		"testdata/v1/argon2id/v0x10/data.txt.abcrypt",
+	} {
+		dataEnc, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var unsupportedKDFErr *abcrypt.UnsupportedKDFError
+		if _, err := abcrypt.NewDecryptor(dataEnc, []byte(passphrase)); !errors.As(err, &unsupportedKDFErr) {
+			t.Errorf("expected error type `%T` for `%v`, got `%T`", unsupportedKDFErr, name, err)
+		}
+
+		if _, err := abcrypt.NewReader(context.Background(), bytes.NewReader(dataEnc), []byte(passphrase)); !errors.As(err, &unsupportedKDFErr) {
+			t.Errorf("expected error type `%T` for `%v`, got `%T`", unsupportedKDFErr, name, err)
+		}
+	}
+
+	dataEnc, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The number of iterations of 0, and the degree of parallelism of 0 and
+	// 256.
+	for _, tc := range []struct {
+		offset int
+		value  uint32
+	}{
+		{20, 0},
+		{24, 0},
+		{24, 256},
+	} {
+		data := bytes.Clone(dataEnc)
+		binary.LittleEndian.PutUint32(data[tc.offset:tc.offset+4], tc.value)
+
+		var unsupportedKDFErr *abcrypt.UnsupportedKDFError
+		if _, err := abcrypt.NewDecryptor(data, []byte(passphrase)); !errors.As(err, &unsupportedKDFErr) {
+			t.Errorf("expected error type `%T` for `%v` at `%v`, got `%T`", unsupportedKDFErr, tc.value, tc.offset, err)
+		}
+
+		if _, err := abcrypt.NewReader(context.Background(), bytes.NewReader(data), []byte(passphrase)); !errors.As(err, &unsupportedKDFErr) {
+			t.Errorf("expected error type `%T` for `%v` at `%v`, got `%T`", unsupportedKDFErr, tc.value, tc.offset, err)
+		}
+	}
+}
+
 func TestDecryptInvalidHeaderMAC(t *testing.T) {
 	t.Parallel()
 
@@ -311,3 +367,166 @@ func TestConvenientDecrypt(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendDecrypt(t *testing.T) {
+	t.Parallel()
+
+	dataEnc, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile("testdata/data.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cipher, err := abcrypt.NewDecryptor(dataEnc, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := []byte("prefix")
+	dst := make([]byte, len(prefix), len(prefix)+cipher.OutLen())
+	copy(dst, prefix)
+
+	out, err := cipher.AppendDecrypt(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if &out[0] != &dst[0] {
+		t.Error("expected the output to reuse dst")
+	}
+
+	if !slices.Equal(out, append(prefix, data...)) {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func TestDecryptInPlace(t *testing.T) {
+	t.Parallel()
+
+	dataEnc, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile("testdata/data.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cipher, err := abcrypt.NewDecryptor(dataEnc, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := cipher.DecryptInPlace()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if &plaintext[0] != &dataEnc[abcrypt.HeaderSize] {
+		t.Error("expected the plaintext to reuse the ciphertext buffer")
+	}
+
+	if !slices.Equal(plaintext, data) {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func TestDecryptInPlaceInvalidMAC(t *testing.T) {
+	t.Parallel()
+
+	dataEnc, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataEnc[len(dataEnc)-1] ^= 1
+
+	cipher, err := abcrypt.NewDecryptor(dataEnc, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cipher.DecryptInPlace()
+
+	var invalidMACError *abcrypt.InvalidMACError
+	if !errors.As(err, &invalidMACError) {
+		t.Fatal("unexpected error type")
+	}
+}
+
+func TestDecryptorWriteTo(t *testing.T) {
+	t.Parallel()
+
+	dataEnc, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile("testdata/data.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cipher, err := abcrypt.NewDecryptor(dataEnc, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	n, err := cipher.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != int64(len(data)) {
+		t.Errorf("expected `%v` bytes written, got `%v`", len(data), n)
+	}
+
+	if !slices.Equal(buf.Bytes(), data) {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func BenchmarkDecrypt(b *testing.B) {
+	ciphertext := abcrypt.EncryptWithParams(make([]byte, 64<<10), []byte(passphrase), 32, 3, 4)
+
+	cipher, err := abcrypt.NewDecryptor(ciphertext, []byte(passphrase))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(cipher.OutLen()))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if _, err := cipher.Decrypt(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAppendDecrypt(b *testing.B) {
+	ciphertext := abcrypt.EncryptWithParams(make([]byte, 64<<10), []byte(passphrase), 32, 3, 4)
+
+	cipher, err := abcrypt.NewDecryptor(ciphertext, []byte(passphrase))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	dst := make([]byte, 0, cipher.OutLen())
+
+	b.SetBytes(int64(cipher.OutLen()))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if dst, err = cipher.AppendDecrypt(dst[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -5,13 +5,16 @@
 package abcrypt
 
 import (
-	"golang.org/x/crypto/argon2"
+	"context"
+	"crypto/cipher"
+	"io"
+
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
 const (
 	defaultArgon2Type    = Argon2id
-	defaultArgon2Version = version0x13
+	defaultArgon2Version = Argon2Version0x13
 	defaultMemoryCost    = 19456
 	defaultTimeCost      = 2
 	defaultParallelism   = 1
@@ -21,7 +24,10 @@ const (
 type Encryptor struct {
 	header    *header
 	dk        *derivedKey
+	aead      cipher.AEAD
 	plaintext []byte
+	observer  Observer
+	pepperID  string
 }
 
 // NewEncryptor creates a new [Encryptor].
@@ -48,54 +54,98 @@ func NewEncryptorWithParams(plaintext, passphrase []byte, memoryCost, timeCost u
 //
 // This uses version 0x13 as the Argon2 version.
 func NewEncryptorWithContext(plaintext, passphrase []byte, argon2Type Argon2Type, memoryCost, timeCost uint32, parallelism uint8) *Encryptor {
-	header := newHeader(argon2Type, defaultArgon2Version, memoryCost, timeCost, uint32(parallelism))
+	opts := []Option{WithArgon2Type(argon2Type), WithParams(memoryCost, timeCost, parallelism)}
 
-	if header.argon2Version == version0x10 {
-		panic("abcrypt: version 0x10 is not supported")
+	e, err := NewEncryptorWithOptions(context.Background(), plaintext, passphrase, opts...)
+	if err != nil {
+		panic(err)
 	}
 
-	s := header.salt[:]
-	t := header.timeCost
-	m := header.memoryCost
-	p := uint8(header.parallelism)
-
-	// The derived key size is 96 bytes. The first 256 bits are for
-	// XChaCha20-Poly1305 key, and the last 512 bits are for
-	// BLAKE2b-512-MAC key.
-	var k []byte
-
-	switch header.argon2Type {
-	case argon2d:
-		panic("abcrypt: Argon2d is not supported")
-	case Argon2i:
-		k = argon2.Key(passphrase, s, t, m, p, derivedKeySize)
-	case Argon2id:
-		k = argon2.IDKey(passphrase, s, t, m, p, derivedKeySize)
+	return e
+}
+
+// NewEncryptorWithOptions creates a new [Encryptor] with the given options.
+//
+// Without options, this uses the same Argon2 type and Argon2 parameters as
+// [NewEncryptor]. ctx is used while waiting for a [MemoryBudget]. This returns
+// an [InvalidArgon2TypeError] or an [InvalidParamsError] if the Argon2 type or
+// the Argon2 parameters are invalid.
+func NewEncryptorWithOptions(ctx context.Context, plaintext, passphrase []byte, opts ...Option) (*Encryptor, error) {
+	o := newOptions(opts)
+
+	if err := o.checkEncrypt(); err != nil {
+		return nil, err
+	}
+
+	header := newHeader(o.argon2Type, defaultArgon2Version, o.memoryCost, o.timeCost, uint32(o.parallelism))
+
+	var (
+		pepperID string
+		pepper   []byte
+	)
+
+	if o.pepper != nil {
+		var err error
+
+		pepperID, pepper, err = currentPepper(o.pepper)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	derivedKey := newDerivedKey([derivedKeySize]byte(k))
+	derivedKey, err := deriveKey(ctx, o, OperationEncrypt, header, passphrase, pepper)
+	if err != nil {
+		return nil, err
+	}
 
 	header.computeMAC(derivedKey.mac[:])
 
-	e := Encryptor{header, derivedKey, plaintext}
+	aead, err := chacha20poly1305.NewX(derivedKey.encrypt[:])
+	if err != nil {
+		panic(err)
+	}
 
-	return &e
+	e := Encryptor{header, derivedKey, aead, plaintext, o.observer, pepperID}
+
+	return &e, nil
+}
+
+// PepperID returns the ID of the pepper used for the key derivation, or an
+// empty string if no pepper was used.
+func (e *Encryptor) PepperID() string {
+	return e.pepperID
 }
 
 // Encrypt encrypts the plaintext and returns the ciphertext.
 func (e *Encryptor) Encrypt() []byte {
+	return e.AppendEncrypt(nil)
+}
+
+// AppendEncrypt encrypts the plaintext, appends the ciphertext to dst and
+// returns the resulting slice.
+//
+// If dst has enough spare capacity for [Encryptor.OutLen] bytes, this does
+// not allocate. dst and the plaintext must not overlap, unless the plaintext
+// starts exactly [HeaderSize] bytes after the end of dst.
+func (e *Encryptor) AppendEncrypt(dst []byte) []byte {
+	ret, out := sliceForAppend(dst, e.OutLen())
+
 	header := e.header.asBytes()
+	copy(out, header[:])
+	e.aead.Seal(out[HeaderSize:HeaderSize], e.header.nonce[:], e.plaintext, nil)
 
-	cipher, err := chacha20poly1305.NewX(e.dk.encrypt[:])
-	if err != nil {
-		panic(err)
-	}
+	e.observer.BytesProcessed(OperationEncrypt, len(e.plaintext))
 
-	ciphertext := cipher.Seal(nil, e.header.nonce[:], e.plaintext, nil)
+	return ret
+}
 
-	out := append(header[:], ciphertext...)
+// WriteTo encrypts the plaintext and writes the ciphertext to w.
+//
+// This implements [io.WriterTo].
+func (e *Encryptor) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(e.Encrypt())
 
-	return out
+	return int64(n), err
 }
 
 // OutLen returns the number of output bytes of the encrypted data.
@@ -138,3 +188,17 @@ func EncryptWithParams(plaintext, passphrase []byte, memoryCost, timeCost uint32
 func EncryptWithContext(plaintext, passphrase []byte, argon2Type Argon2Type, memoryCost, timeCost uint32, parallelism uint8) []byte {
 	return NewEncryptorWithContext(plaintext, passphrase, argon2Type, memoryCost, timeCost, parallelism).Encrypt()
 }
+
+// EncryptWithOptions encrypts the plaintext with the given options and returns
+// the ciphertext.
+//
+// This is a convenience function for using [NewEncryptorWithOptions] and
+// [Encryptor.Encrypt].
+func EncryptWithOptions(ctx context.Context, plaintext, passphrase []byte, opts ...Option) ([]byte, error) {
+	cipher, err := NewEncryptorWithOptions(ctx, plaintext, passphrase, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.Encrypt(), nil
+}
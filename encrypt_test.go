@@ -5,7 +5,10 @@
 package abcrypt_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"os"
 	"slices"
 	"testing"
@@ -187,6 +190,26 @@ func TestEncryptWithContext(t *testing.T) {
 	}
 }
 
+func TestEncryptWithOptionsInvalid(t *testing.T) {
+	t.Parallel()
+
+	var argon2TypeErr *abcrypt.InvalidArgon2TypeError
+	if _, err := abcrypt.NewEncryptorWithOptions(context.Background(), []byte(data), []byte(passphrase), abcrypt.WithArgon2Type(7)); !errors.As(err, &argon2TypeErr) {
+		t.Errorf("expected error type `%T`, got `%T`", argon2TypeErr, err)
+	}
+
+	for _, opt := range []abcrypt.Option{
+		abcrypt.WithParams(32, 0, 1),
+		abcrypt.WithParams(32, 3, 0),
+		abcrypt.WithParams(7, 3, 1),
+	} {
+		var paramsErr *abcrypt.InvalidParamsError
+		if _, err := abcrypt.NewEncryptorWithOptions(context.Background(), []byte(data), []byte(passphrase), opt); !errors.As(err, &paramsErr) {
+			t.Errorf("expected error type `%T`, got `%T`", paramsErr, err)
+		}
+	}
+}
+
 func TestEncryptMinimumOutputLength(t *testing.T) {
 	t.Parallel()
 
@@ -462,3 +485,101 @@ func TestConvenientEncryptWithContext(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendEncrypt(t *testing.T) {
+	t.Parallel()
+
+	cipher := abcrypt.NewEncryptorWithParams([]byte(data), []byte(passphrase), 32, 3, 4)
+
+	prefix := []byte("prefix")
+	dst := make([]byte, len(prefix), len(prefix)+cipher.OutLen())
+	copy(dst, prefix)
+
+	out := cipher.AppendEncrypt(dst)
+	if &out[0] != &dst[0] {
+		t.Error("expected the output to reuse dst")
+	}
+
+	if !slices.Equal(out[:len(prefix)], prefix) {
+		t.Error("unexpected mismatch of the prefix")
+	}
+
+	plaintext, err := abcrypt.Decrypt(out[len(prefix):], []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func TestAppendEncryptInPlace(t *testing.T) {
+	t.Parallel()
+
+	buf := make([]byte, abcrypt.HeaderSize+len(data)+abcrypt.TagSize)
+	copy(buf[abcrypt.HeaderSize:], data)
+
+	cipher := abcrypt.NewEncryptorWithParams(buf[abcrypt.HeaderSize:abcrypt.HeaderSize+len(data)], []byte(passphrase), 32, 3, 4)
+	out := cipher.AppendEncrypt(buf[:0])
+
+	plaintext, err := abcrypt.Decrypt(out, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func TestEncryptorWriteTo(t *testing.T) {
+	t.Parallel()
+
+	cipher := abcrypt.NewEncryptorWithParams([]byte(data), []byte(passphrase), 32, 3, 4)
+
+	var buf bytes.Buffer
+
+	n, err := cipher.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != int64(cipher.OutLen()) {
+		t.Errorf("expected `%v` bytes written, got `%v`", cipher.OutLen(), n)
+	}
+
+	plaintext, err := abcrypt.Decrypt(buf.Bytes(), []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func BenchmarkEncrypt(b *testing.B) {
+	plaintext := make([]byte, 64<<10)
+	cipher := abcrypt.NewEncryptorWithParams(plaintext, []byte(passphrase), 32, 3, 4)
+
+	b.SetBytes(int64(len(plaintext)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		cipher.Encrypt()
+	}
+}
+
+func BenchmarkAppendEncrypt(b *testing.B) {
+	plaintext := make([]byte, 64<<10)
+	cipher := abcrypt.NewEncryptorWithParams(plaintext, []byte(passphrase), 32, 3, 4)
+	dst := make([]byte, 0, cipher.OutLen())
+
+	b.SetBytes(int64(len(plaintext)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		dst = cipher.AppendEncrypt(dst[:0])
+	}
+}
@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"context"
+	"strings"
+)
+
+// LoadEnv decrypts the ciphertext and parses the plaintext as the dotenv
+// format with [ParseEnv].
+//
+// The plaintext is cleared after parsing. The returned map does not refer to
+// the plaintext, but its strings cannot be cleared and remain in memory until
+// they are garbage collected.
+func LoadEnv(ciphertext, passphrase []byte) (map[string]string, error) {
+	return LoadEnvWithOptions(context.Background(), ciphertext, passphrase)
+}
+
+// LoadEnvWithOptions is like [LoadEnv], but decrypts the ciphertext with the
+// given options. The options are the same as [NewDecryptorWithOptions].
+func LoadEnvWithOptions(ctx context.Context, ciphertext, passphrase []byte, opts ...Option) (map[string]string, error) {
+	plaintext, err := DecryptWithOptions(ctx, ciphertext, passphrase, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+
+	return ParseEnv(plaintext)
+}
+
+// ParseEnv parses the dotenv format data and returns the variables.
+//
+// Each entry is a line of the form KEY=VALUE, optionally preceded by
+// "export". The key consists of ASCII letters, digits and underscores, and
+// does not start with a digit. Whitespace around the key and the value is
+// ignored. Empty lines and lines starting with "#" are ignored, and "#"
+// preceded by whitespace starts a comment after the value.
+//
+// The value is one of:
+//
+//   - An unquoted value, which is taken literally up to the end of the line or
+//     a comment.
+//   - A single-quoted value, which is taken literally and may span multiple
+//     lines.
+//   - A double-quoted value, which may span multiple lines and supports the
+//     escape sequences \n, \r, \t, \\, \", \' and \$.
+//
+// Variables are not expanded. If a key appears more than once, the last
+// value is used. An invalid entry results in a [ParseEnvError]. The keys and
+// the values are copied, so the returned map does not keep data reachable.
+func ParseEnv(data []byte) (map[string]string, error) {
+	p := envParser{s: string(data), line: 1}
+	env := make(map[string]string)
+
+	for {
+		p.skipBlank()
+
+		if p.eof() {
+			return env, nil
+		}
+
+		key, value, err := p.parseEntry()
+		if err != nil {
+			return nil, err
+		}
+
+		// Copy the key and the value so that they do not keep the whole
+		// data reachable.
+		env[strings.Clone(key)] = strings.Clone(value)
+	}
+}
+
+// envParser represents the state of [ParseEnv].
+type envParser struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (p *envParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *envParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.s[p.pos]
+}
+
+// next returns the current byte and advances, counting the lines.
+func (p *envParser) next() byte {
+	c := p.s[p.pos]
+	p.pos++
+
+	if c == '\n' {
+		p.line++
+	}
+
+	return c
+}
+
+// skipSpaces skips spaces and tabs within the line.
+func (p *envParser) skipSpaces() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.pos++
+	}
+}
+
+// skipComment skips the rest of the line if it is a comment, and leaves the
+// newline.
+func (p *envParser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+// skipBlank skips empty lines and comment lines.
+func (p *envParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n':
+			p.next()
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// endLine checks that only whitespace or a comment follows the value, and
+// advances to the next line.
+func (p *envParser) endLine(line int) error {
+	p.skipSpaces()
+	p.skipComment()
+
+	if p.peek() == '\r' {
+		p.pos++
+	}
+
+	if p.eof() {
+		return nil
+	}
+
+	if p.peek() != '\n' {
+		return &ParseEnvError{line, "unexpected character after the value"}
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *envParser) parseEntry() (string, string, error) {
+	line := p.line
+
+	key := p.parseKey()
+	if key == "export" && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipSpaces()
+		key = p.parseKey()
+	}
+
+	if key == "" {
+		return "", "", &ParseEnvError{line, "invalid key"}
+	}
+
+	p.skipSpaces()
+
+	if p.peek() != '=' {
+		return "", "", &ParseEnvError{line, "missing `=` after the key"}
+	}
+
+	p.pos++
+	p.skipSpaces()
+
+	var (
+		value string
+		err   error
+	)
+
+	switch p.peek() {
+	case '\'':
+		value, err = p.parseSingleQuoted(line)
+	case '"':
+		value, err = p.parseDoubleQuoted(line)
+	default:
+		value = p.parseUnquoted()
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if strings.IndexByte(value, 0) >= 0 {
+		return "", "", &ParseEnvError{line, "value contains a NUL byte"}
+	}
+
+	if err := p.endLine(line); err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+func (p *envParser) parseKey() string {
+	start := p.pos
+
+	for !p.eof() {
+		c := p.peek()
+		if c == '_' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || p.pos > start && '0' <= c && c <= '9' {
+			p.pos++
+
+			continue
+		}
+
+		break
+	}
+
+	return p.s[start:p.pos]
+}
+
+// parseUnquoted returns the value up to the end of the line or a comment,
+// without the trailing whitespace.
+//
+// A comment starts with '#' after whitespace, which may have been skipped
+// before the value, so the byte before the value is also checked.
+func (p *envParser) parseUnquoted() string {
+	start := p.pos
+
+	for !p.eof() {
+		c := p.peek()
+		if c == '\n' || c == '#' && p.pos > 0 && (p.s[p.pos-1] == ' ' || p.s[p.pos-1] == '\t') {
+			break
+		}
+
+		p.pos++
+	}
+
+	return strings.TrimRight(p.s[start:p.pos], " \t\r")
+}
+
+func (p *envParser) parseSingleQuoted(line int) (string, error) {
+	p.pos++
+	start := p.pos
+
+	for !p.eof() {
+		if p.peek() == '\'' {
+			value := p.s[start:p.pos]
+			p.pos++
+
+			return value, nil
+		}
+
+		p.next()
+	}
+
+	return "", &ParseEnvError{line, "unterminated quoted value"}
+}
+
+func (p *envParser) parseDoubleQuoted(line int) (string, error) {
+	p.pos++
+
+	var b strings.Builder
+
+	for !p.eof() {
+		c := p.next()
+
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", &ParseEnvError{line, "unterminated quoted value"}
+			}
+
+			switch e := p.next(); e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '\\', '"', '\'', '$':
+				b.WriteByte(e)
+			default:
+				return "", &ParseEnvError{line, "invalid escape sequence `\\" + string(e) + "`"}
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", &ParseEnvError{line, "unterminated quoted value"}
+}
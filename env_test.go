@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+const envData = `# Database
+export DB_HOST=localhost
+DB_PORT = 5432 # inline comment
+DB_URL=postgres://localhost/db#fragment
+EMPTY=
+COMMENT= # only a comment
+TAB=` + "\t" + `# only a comment
+SINGLE='literal \n $HOME # not a comment'
+DOUBLE="line1\nline2\t\"quoted\" \$HOME \\"
+MULTI="-----BEGIN KEY-----
+abc
+-----END KEY-----"
+CRLF=value` + "\r\n" + `DB_PORT=5433
+export=1
+`
+
+func TestParseEnv(t *testing.T) {
+	t.Parallel()
+
+	env, err := abcrypt.ParseEnv([]byte(envData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"DB_HOST": "localhost",
+		"DB_PORT": "5433",
+		"DB_URL":  "postgres://localhost/db#fragment",
+		"EMPTY":   "",
+		"COMMENT": "",
+		"TAB":     "",
+		"SINGLE":  `literal \n $HOME # not a comment`,
+		"DOUBLE":  "line1\nline2\t\"quoted\" $HOME \\",
+		"MULTI":   "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+		"CRLF":    "value",
+		"export":  "1",
+	}
+
+	if !maps.Equal(env, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, env)
+	}
+}
+
+func TestParseEnvWithInvalidEntry(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		data string
+		line int
+	}{
+		{"A=1\n1A=2\n", 2},
+		{"A=1\n\nB\n", 3},
+		{"A-B=1", 1},
+		{"A='unterminated\n", 1},
+		{"A=1\nB=\"unterminated\\\"\n", 2},
+		{`A="\x"`, 1},
+		{`A="quoted" trailing`, 1},
+		{"A=\"multi\nline\" trailing\n", 1},
+		{"A=\"\\0\"", 1},
+		{"A=a\x00b", 1},
+	} {
+		_, err := abcrypt.ParseEnv([]byte(tc.data))
+
+		var parseEnvErr *abcrypt.ParseEnvError
+		if !errors.As(err, &parseEnvErr) {
+			t.Errorf("expected error type `%T` for `%q`, got `%T`", parseEnvErr, tc.data, err)
+
+			continue
+		}
+
+		if parseEnvErr.Line != tc.line {
+			t.Errorf("expected line `%v` for `%q`, got `%v`", tc.line, tc.data, parseEnvErr.Line)
+		}
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte("KEY=value\n"), []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := abcrypt.LoadEnv(ciphertext, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if env["KEY"] != "value" {
+		t.Errorf("expected `%v`, got `%v`", "value", env["KEY"])
+	}
+
+	var invalidHeaderMACErr *abcrypt.InvalidHeaderMACError
+	if _, err := abcrypt.LoadEnv(ciphertext, []byte("password")); !errors.As(err, &invalidHeaderMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", invalidHeaderMACErr, err)
+	}
+}
@@ -17,6 +17,28 @@ var ErrInvalidLength = errors.New("abcrypt: encrypted data is shorter than 164 b
 // signature) was invalid.
 var ErrInvalidMagicNumber = errors.New("abcrypt: invalid magic number")
 
+// ErrInvalidDerivedKeyLength represents an error due to a [KeyDeriver]
+// returned a key whose length was not 96 bytes.
+var ErrInvalidDerivedKeyLength = errors.New("abcrypt: derived key is not 96 bytes")
+
+// ErrInvalidSaltLength represents an error due to the salt was not 32 bytes.
+var ErrInvalidSaltLength = errors.New("abcrypt: salt is not 32 bytes")
+
+// ErrNoPepper represents an error due to a [PepperSource] provided no pepper.
+var ErrNoPepper = errors.New("abcrypt: no pepper is available")
+
+// ErrInvalidPepperLength represents an error due to a pepper was empty or
+// longer than 64 bytes.
+var ErrInvalidPepperLength = errors.New("abcrypt: pepper is not 1 to 64 bytes")
+
+// ErrVerificationFailed represents an error due to the output file did not
+// match the input when it was read back.
+var ErrVerificationFailed = errors.New("abcrypt: output file does not match the input")
+
+// ErrUnsupportedFileType represents an error due to a file or an entry of an
+// archive was neither a directory, a regular file nor a symbolic link.
+var ErrUnsupportedFileType = errors.New("abcrypt: unsupported file type")
+
 // UnsupportedVersionError represents an error due to the version was the
 // unsupported abcrypt version number.
 type UnsupportedVersionError struct {
@@ -65,6 +87,19 @@ func (e *InvalidArgon2VersionError) Error() string {
 	return fmt.Sprintf("abcrypt: invalid Argon2 version `%#x`", e.Version)
 }
 
+// UnsupportedKDFError represents an error due to the key derivation with the
+// Argon2 type, the Argon2 version or the degree of parallelism was not
+// supported by this package, although the encrypted data was valid.
+type UnsupportedKDFError struct {
+	// Reason represents the reason why the key derivation is not supported.
+	Reason string
+}
+
+// Error returns a string representation of an [UnsupportedKDFError].
+func (e *UnsupportedKDFError) Error() string {
+	return "abcrypt: unsupported key derivation: " + e.Reason
+}
+
 // InvalidHeaderMACError represents an error due to the MAC (authentication
 // tag) of the header was invalid.
 type InvalidHeaderMACError struct {
@@ -93,3 +128,102 @@ func (e *InvalidMACError) Error() string {
 func (e *InvalidMACError) Unwrap() error {
 	return e.Err
 }
+
+// MemoryBudgetError represents an error due to the memory required for the
+// key derivation could not be acquired from a [MemoryBudget].
+type MemoryBudgetError struct {
+	// Requested represents the requested memory size in KiB.
+	Requested uint64
+
+	// Available represents the memory size in KiB which was available.
+	Available uint64
+
+	// Limit represents the total memory size in KiB of the budget.
+	Limit uint64
+}
+
+// Error returns a string representation of a [MemoryBudgetError].
+func (e *MemoryBudgetError) Error() string {
+	return fmt.Sprintf("abcrypt: memory budget exceeded: requested %v KiB, available %v KiB of %v KiB", e.Requested, e.Available, e.Limit)
+}
+
+// InvalidParamsError represents an error due to the Argon2 parameters were
+// invalid.
+type InvalidParamsError struct {
+	// Param represents the name of the invalid parameter.
+	Param string
+
+	// Value represents the obtained value of the parameter.
+	Value uint32
+
+	// Reason represents the reason why the value is invalid.
+	Reason string
+}
+
+// Error returns a string representation of an [InvalidParamsError].
+func (e *InvalidParamsError) Error() string {
+	return fmt.Sprintf("abcrypt: invalid `%v` `%v`: %v", e.Param, e.Value, e.Reason)
+}
+
+// ParseParamsError represents an error due to the Argon2 parameters string
+// could not be parsed.
+type ParseParamsError struct {
+	// Input represents the string which could not be parsed.
+	Input string
+}
+
+// Error returns a string representation of a [ParseParamsError].
+func (e *ParseParamsError) Error() string {
+	return fmt.Sprintf("abcrypt: invalid Argon2 parameters `%v`", e.Input)
+}
+
+// ParseArgon2TypeError represents an error due to the name of the Argon2 type
+// could not be parsed.
+type ParseArgon2TypeError struct {
+	// Input represents the string which could not be parsed.
+	Input string
+}
+
+// Error returns a string representation of a [ParseArgon2TypeError].
+func (e *ParseArgon2TypeError) Error() string {
+	return fmt.Sprintf("abcrypt: unknown Argon2 type `%v`", e.Input)
+}
+
+// ParseArgon2VersionError represents an error due to the Argon2 version could
+// not be parsed.
+type ParseArgon2VersionError struct {
+	// Input represents the string which could not be parsed.
+	Input string
+}
+
+// Error returns a string representation of a [ParseArgon2VersionError].
+func (e *ParseArgon2VersionError) Error() string {
+	return fmt.Sprintf("abcrypt: unknown Argon2 version `%v`", e.Input)
+}
+
+// UnknownPepperError represents an error due to a [PepperSource] did not have
+// the pepper with the given ID.
+type UnknownPepperError struct {
+	// ID represents the ID of the pepper.
+	ID string
+}
+
+// Error returns a string representation of an [UnknownPepperError].
+func (e *UnknownPepperError) Error() string {
+	return fmt.Sprintf("abcrypt: unknown pepper `%v`", e.ID)
+}
+
+// ParseEnvError represents an error due to the dotenv format data could not
+// be parsed.
+type ParseEnvError struct {
+	// Line represents the line number where the invalid entry starts.
+	Line int
+
+	// Reason represents the reason why the entry is invalid.
+	Reason string
+}
+
+// Error returns a string representation of a [ParseEnvError].
+func (e *ParseEnvError) Error() string {
+	return fmt.Sprintf("abcrypt: invalid dotenv entry at line `%v`: %v", e.Line, e.Reason)
+}
@@ -37,6 +37,28 @@ func TestErrInvalidMagicNumber(t *testing.T) {
 	}
 }
 
+func TestErrInvalidDerivedKeyLength(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ErrInvalidDerivedKeyLength
+	expected := "abcrypt: derived key is not 96 bytes"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestErrInvalidSaltLength(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ErrInvalidSaltLength
+	expected := "abcrypt: salt is not 32 bytes"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
 func TestUnsupportedVersionError(t *testing.T) {
 	t.Parallel()
 
@@ -97,6 +119,17 @@ func TestInvalidArgon2VersionError(t *testing.T) {
 	}
 }
 
+func TestUnsupportedKDFError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.UnsupportedKDFError{"Argon2d is not supported"}
+	expected := "abcrypt: unsupported key derivation: Argon2d is not supported"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
 func TestInvalidHeaderMACError(t *testing.T) {
 	t.Parallel()
 
@@ -127,3 +160,124 @@ func TestInvalidMACError(t *testing.T) {
 		t.Error("unexpected error message")
 	}
 }
+
+func TestMemoryBudgetError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.MemoryBudgetError{65, 64, 64}
+	expected := "abcrypt: memory budget exceeded: requested 65 KiB, available 64 KiB of 64 KiB"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestInvalidParamsError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.InvalidParamsError{"timeCost", 0, "must be at least 1"}
+	expected := "abcrypt: invalid `timeCost` `0`: must be at least 1"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestParseParamsError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ParseParamsError{"m=19456"}
+	expected := "abcrypt: invalid Argon2 parameters `m=19456`"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestParseArgon2TypeError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ParseArgon2TypeError{"argon2"}
+	expected := "abcrypt: unknown Argon2 type `argon2`"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestParseArgon2VersionError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ParseArgon2VersionError{"v19"}
+	expected := "abcrypt: unknown Argon2 version `v19`"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestErrNoPepper(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ErrNoPepper
+	expected := "abcrypt: no pepper is available"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestErrInvalidPepperLength(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ErrInvalidPepperLength
+	expected := "abcrypt: pepper is not 1 to 64 bytes"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestErrVerificationFailed(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ErrVerificationFailed
+	expected := "abcrypt: output file does not match the input"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestErrUnsupportedFileType(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ErrUnsupportedFileType
+	expected := "abcrypt: unsupported file type"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestUnknownPepperError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.UnknownPepperError{"v1"}
+	expected := "abcrypt: unknown pepper `v1`"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestParseEnvError(t *testing.T) {
+	t.Parallel()
+
+	err := abcrypt.ParseEnvError{3, "unterminated quoted value"}
+	expected := "abcrypt: invalid dotenv entry at line `3`: unterminated quoted value"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
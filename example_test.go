@@ -97,3 +97,17 @@ func ExampleParams() {
 	// timeCost: 3
 	// parallelism: 4
 }
+
+func ExampleParseParams() {
+	params, err := abcrypt.ParseParams("m=19456,t=2,p=1")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("memory size: %v B\n", params.MemoryBytes())
+	fmt.Printf("parameters: %v\n", params)
+
+	// Output:
+	// memory size: 19922944 B
+	// parameters: m=19456,t=2,p=1
+}
@@ -9,10 +9,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+
+	"github.com/sorairolake/abcrypt-go/examples"
 )
 
 type options struct {
 	output  string
+	keyfile string
+	force   bool
 	version bool
 }
 
@@ -20,10 +24,13 @@ var opt options
 
 func init() {
 	flag.StringVar(&opt.output, "output", "", "Output the result to a file")
+	flag.StringVar(&opt.keyfile, "keyfile", "", "Use the keyfile given at the time of encryption")
+	flag.BoolVar(&opt.force, "force", false, "Overwrite the output file if it exists")
+	flag.BoolVar(&examples.JSONErrors, "json-errors", false, "Print errors as JSON objects")
 	flag.BoolVar(&opt.version, "version", false, "Print version number")
 
 	flag.Usage = func() {
-		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] <FILE>\n", os.Args[0]); err != nil {
+		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] [FILE]\n", os.Args[0]); err != nil {
 			log.Fatal(err)
 		}
 
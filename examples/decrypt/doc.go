@@ -5,19 +5,37 @@
 // Decrypt is an example of decrypting a file from the abcrypt encrypted data
 // format.
 //
+// The data is processed in chunks, so the memory usage does not depend on the
+// size of the input. If the argument is omitted or is "-", the standard input
+// is used, and the passphrase is prompted for on the controlling terminal.
+// Since the MAC of the ciphertext is at the end of the data, a part of the
+// plaintext may have been written to the standard output when the MAC turns
+// out to be invalid.
+//
+// If ABCRYPT_AGENT_SOCK is set, the passphrase held by abcrypt-agent is used
+// instead of prompting for it, unless -keyfile is specified.
+//
 // Usage:
 //
-//	decrypt [OPTIONS] <FILE>
+//	decrypt [OPTIONS] [FILE]
 //
 // Arguments:
 //
-//	<FILE>
+//	[FILE]
 //		Input file.
 //
 // Options:
 //
 //	-output <FILE>
 //		Output the result to a file.
+//	-force
+//		Overwrite the output file if it exists.
+//	-keyfile <FILE>
+//		Use the keyfile given at the time of encryption.
+//	-json-errors
+//		Print errors as JSON objects.
 //	-version
 //		Print version number.
+//
+// The exit status is described in the examples package.
 package main
@@ -5,15 +5,15 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
-	"syscall"
 
 	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/agent"
 	"github.com/sorairolake/abcrypt-go/examples"
-	"golang.org/x/term"
 )
 
 func main() {
@@ -25,36 +25,71 @@ func main() {
 		os.Exit(0)
 	}
 
-	if flag.NArg() != 1 {
+	if flag.NArg() > 1 {
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(examples.ExitUsage)
 	}
 
-	ciphertext, err := os.ReadFile(args[0])
-	if err != nil {
-		log.Fatal(err)
+	in := os.Stdin
+
+	if flag.NArg() == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			examples.Fatal(err)
+		}
+		defer f.Close()
+
+		in = f
 	}
 
-	fmt.Print("Enter passphrase: ")
+	var (
+		passphrase []byte
+		opts       []abcrypt.Option
+	)
 
-	passphrase, err := term.ReadPassword(int(syscall.Stdin))
-	if err != nil {
-		log.Fatal(err)
+	if opt.keyfile != "" {
+		keyfile, err := os.ReadFile(opt.keyfile)
+		if err != nil {
+			examples.Fatal(err)
+		}
+
+		opts = append(opts, abcrypt.WithKeyfile(keyfile))
 	}
-	fmt.Println()
 
-	plaintext, err := abcrypt.Decrypt(ciphertext, passphrase)
-	if err != nil {
-		log.Fatal(err)
+	// The agent holds only the passphrase, so it is not used with a keyfile.
+	if client, err := agent.NewClientFromEnv(); err == nil && opt.keyfile == "" {
+		opts = append(opts, abcrypt.WithKeyDeriver(client))
+	} else {
+		passphrase, err = examples.ReadPassphrase("Enter passphrase: ")
+		if err != nil {
+			examples.Fatal(err)
+		}
 	}
 
-	if opt.output == "" {
-		if _, err := os.Stdout.Write(plaintext); err != nil {
-			log.Fatal(err)
+	ctx := context.Background()
+
+	// The ciphertext is decrypted in chunks, so the memory usage does not
+	// depend on the size of the input.
+	if opt.output != "" && opt.output != "-" {
+		if opt.force {
+			opts = append(opts, abcrypt.WithOverwrite())
 		}
-	} else {
-		if err := os.WriteFile(opt.output, plaintext, os.ModeType); err != nil {
-			log.Fatal(err)
+
+		// The output file is renamed into place only after the MAC of the
+		// ciphertext has been verified.
+		if err := abcrypt.DecryptFile(ctx, opt.output, in, passphrase, opts...); err != nil {
+			examples.Fatal(err)
 		}
+
+		return
+	}
+
+	r, err := abcrypt.NewReader(ctx, in, passphrase, opts...)
+	if err != nil {
+		examples.Fatal(err)
+	}
+
+	if _, err := io.Copy(os.Stdout, r); err != nil {
+		examples.Fatal(err)
 	}
 }
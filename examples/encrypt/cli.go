@@ -11,34 +11,40 @@ import (
 	"os"
 
 	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/examples"
 )
 
 const (
-	defaultArgon2Type  = uint(abcrypt.Argon2id)
 	defaultMemoryCost  = 19456
 	defaultTimeCost    = 2
 	defaultParallelism = 1
 )
 
 type options struct {
-	argon2Type  uint
+	argon2Type  abcrypt.Argon2Type
 	memoryCost  uint
 	timeCost    uint
 	parallelism uint
+	keyfile     string
+	force       bool
 	version     bool
 }
 
 var opt options
 
 func init() {
-	flag.UintVar(&opt.argon2Type, "argon2-type", defaultArgon2Type, "Set the Argon2 type")
+	opt.argon2Type = abcrypt.Argon2id
+	flag.Var(&opt.argon2Type, "argon2-type", "Set the Argon2 type (argon2i or argon2id)")
 	flag.UintVar(&opt.memoryCost, "memory-cost", defaultMemoryCost, "Set the memory size in KiB")
 	flag.UintVar(&opt.timeCost, "time-cost", defaultTimeCost, "Set the number of iterations")
 	flag.UintVar(&opt.parallelism, "parallelism", defaultParallelism, "Set the degree of parallelism")
+	flag.StringVar(&opt.keyfile, "keyfile", "", "Require the contents of a keyfile in addition to the passphrase")
+	flag.BoolVar(&opt.force, "force", false, "Overwrite the output file if it exists")
+	flag.BoolVar(&examples.JSONErrors, "json-errors", false, "Print errors as JSON objects")
 	flag.BoolVar(&opt.version, "version", false, "Print version number")
 
 	flag.Usage = func() {
-		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] <INFILE> <OUTFILE>\n", os.Args[0]); err != nil {
+		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] [INFILE] [OUTFILE]\n", os.Args[0]); err != nil {
 			log.Fatal(err)
 		}
 
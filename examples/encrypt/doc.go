@@ -5,27 +5,44 @@
 // Encrypt is an example of encrypting a file to the abcrypt encrypted data
 // format.
 //
+// The data is processed in chunks, so the memory usage does not depend on the
+// size of the input. If an argument is omitted or is "-", the standard input
+// or the standard output is used, and the passphrase is prompted for on the
+// controlling terminal.
+//
+// If ABCRYPT_AGENT_SOCK is set, the passphrase held by abcrypt-agent is used
+// instead of prompting for it, unless -keyfile is specified.
+//
 // Usage:
 //
-//	encrypt [OPTIONS] <INFILE> <OUTFILE>
+//	encrypt [OPTIONS] [INFILE] [OUTFILE]
 //
 // Arguments:
 //
-//	<INFILE>
+//	[INFILE]
 //		Input file.
-//	<OUTFILE>
+//	[OUTFILE]
 //		Output file.
 //
 // Options:
 //
 //	-argon2-type <TYPE>
-//		Set the Argon2 type.
+//		Set the Argon2 type (argon2i or argon2id).
 //	-memory-cost <NUM>
 //		Set the memory size in KiB.
 //	-time-cost <NUM>
 //		Set the number of iterations.
 //	-parallelism <NUM>
 //		Set the degree of parallelism.
+//	-force
+//		Overwrite the output file if it exists.
+//	-keyfile <FILE>
+//		Require the contents of a keyfile in addition to the passphrase.
+//		A keyfile can be generated with abcrypt-keyfile.
+//	-json-errors
+//		Print errors as JSON objects.
 //	-version
 //		Print version number.
+//
+// The exit status is described in the examples package.
 package main
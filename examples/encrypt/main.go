@@ -5,15 +5,16 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
-	"syscall"
 
 	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/agent"
 	"github.com/sorairolake/abcrypt-go/examples"
-	"golang.org/x/term"
 )
 
 func main() {
@@ -25,31 +26,102 @@ func main() {
 		os.Exit(0)
 	}
 
-	if flag.NArg() != 2 {
+	if flag.NArg() > 2 {
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(examples.ExitUsage)
 	}
 
-	plaintext, err := os.ReadFile(args[0])
-	if err != nil {
-		log.Fatal(err)
+	// An omitted argument or "-" means the standard input or the standard
+	// output.
+	input, output := "-", "-"
+	if flag.NArg() > 0 {
+		input = args[0]
+	}
+
+	if flag.NArg() > 1 {
+		output = args[1]
 	}
 
-	fmt.Print("Enter passphrase: ")
+	in := os.Stdin
 
-	passphrase, err := term.ReadPassword(int(syscall.Stdin))
-	if err != nil {
-		log.Fatal(err)
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			examples.Fatal(err)
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	var (
+		passphrase []byte
+		opts       []abcrypt.Option
+	)
+
+	if opt.keyfile != "" {
+		keyfile, err := os.ReadFile(opt.keyfile)
+		if err != nil {
+			examples.Fatal(err)
+		}
+
+		opts = append(opts, abcrypt.WithKeyfile(keyfile))
+	}
+
+	// The agent holds only the passphrase, so it is not used with a keyfile.
+	if client, err := agent.NewClientFromEnv(); err == nil && opt.keyfile == "" {
+		opts = append(opts, abcrypt.WithKeyDeriver(client))
+	} else {
+		passphrase, err = examples.ReadPassphrase("Enter passphrase: ")
+		if err != nil {
+			examples.Fatal(err)
+		}
+	}
+
+	if opt.argon2Type != abcrypt.Argon2i && opt.argon2Type != abcrypt.Argon2id {
+		examples.Fatal(fmt.Errorf("%v is not supported for encryption", opt.argon2Type))
 	}
-	fmt.Println()
 
-	argon2Type := abcrypt.Argon2Type(opt.argon2Type)
+	argon2Type := opt.argon2Type
 	m := uint32(opt.memoryCost)
 	t := uint32(opt.timeCost)
 	p := uint8(opt.parallelism)
-	ciphertext := abcrypt.EncryptWithContext(plaintext, passphrase, argon2Type, m, t, p)
+	opts = append(opts, abcrypt.WithArgon2Type(argon2Type), abcrypt.WithParams(m, t, p))
+
+	ctx := context.Background()
+
+	// The plaintext is encrypted in chunks, so the memory usage does not
+	// depend on the size of the input.
+	if output != "-" {
+		if opt.force {
+			opts = append(opts, abcrypt.WithOverwrite())
+		}
+
+		// The output file is written to a temporary file and renamed into
+		// place, so it is never left partially written.
+		if err := abcrypt.EncryptFile(ctx, output, in, passphrase, opts...); err != nil {
+			examples.Fatal(err)
+		}
+
+		return
+	}
+
+	bw := bufio.NewWriter(os.Stdout)
+
+	w, err := abcrypt.NewWriter(ctx, bw, passphrase, opts...)
+	if err != nil {
+		examples.Fatal(err)
+	}
+
+	if _, err := io.Copy(w, in); err != nil {
+		examples.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		examples.Fatal(err)
+	}
 
-	if err := os.WriteFile(args[1], ciphertext, os.ModeType); err != nil {
-		log.Fatal(err)
+	if err := bw.Flush(); err != nil {
+		examples.Fatal(err)
 	}
 }
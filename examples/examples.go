@@ -3,7 +3,24 @@
 // SPDX-License-Identifier: Apache-2.0 OR MIT
 
 // Package examples contains sample applications for the module.
+//
+// The applications exit with the status below, which is mapped from the
+// error types of the abcrypt package by [ExitCode]:
+//
+//	0	Success.
+//	1	An error occurred.
+//	2	The command line was invalid.
+//	3	The passphrase or the keyfile was wrong, or the header was modified.
+//	4	The payload was corrupted or truncated.
+//	5	The data was not in the abcrypt encrypted data format.
+//	6	The version of the format or the Argon2 type or version was not
+//		supported.
+//	7	A file could not be read or written.
+//
+// With -json-errors, the error is printed as an [ErrorReport] in JSON.
 package examples
 
+import "github.com/sorairolake/abcrypt-go/internal/cli"
+
 // Version represents the version number of the module.
-const Version = "0.3.1"
+const Version = cli.Version
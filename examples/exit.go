@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+)
+
+// Exit codes of the commands.
+const (
+	// ExitSuccess indicates that the command succeeded.
+	ExitSuccess = cli.ExitSuccess
+
+	// ExitFailure indicates an error which has no specific exit code.
+	ExitFailure = cli.ExitFailure
+
+	// ExitUsage indicates that the command line was invalid.
+	ExitUsage = cli.ExitUsage
+
+	// ExitInvalidPassphrase indicates that the MAC of the header was invalid,
+	// that is, the passphrase or the keyfile was wrong, or the header was
+	// modified.
+	ExitInvalidPassphrase = cli.ExitInvalidPassphrase
+
+	// ExitCorrupted indicates that the MAC of the ciphertext was invalid, that
+	// is, the payload was corrupted or truncated.
+	ExitCorrupted = cli.ExitCorrupted
+
+	// ExitNotAbcrypt indicates that the data was not in the abcrypt encrypted
+	// data format.
+	ExitNotAbcrypt = cli.ExitNotAbcrypt
+
+	// ExitUnsupported indicates that the version of the format, the Argon2
+	// type or version, or the degree of parallelism was not supported.
+	ExitUnsupported = cli.ExitUnsupported
+
+	// ExitIO indicates an error of reading or writing a file.
+	ExitIO = cli.ExitIO
+)
+
+// ExitCode returns the exit code for err, which is mapped from the error
+// types of the abcrypt package. It returns [ExitSuccess] if err is nil.
+//
+// If err wraps an error which has the ExitCode method, such as an error due to
+// the command line, its result is returned.
+func ExitCode(err error) int {
+	return cli.ExitCode(err)
+}
+
+// ErrorReport represents a machine-readable error.
+type ErrorReport = cli.ErrorReport
+
+// NewErrorReport returns a new [ErrorReport] for err.
+func NewErrorReport(err error) *ErrorReport {
+	return cli.NewErrorReport(err)
+}
+
+// JSONErrors reports whether [PrintError] and [Fatal] print the error as an
+// [ErrorReport] in JSON.
+var JSONErrors bool
+
+// PrintError prints err to standard error, as an [ErrorReport] in JSON if
+// [JSONErrors] is true.
+func PrintError(err error) {
+	if JSONErrors {
+		b, _ := json.Marshal(NewErrorReport(err))
+		fmt.Fprintln(os.Stderr, string(b))
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
+
+// Fatal prints err to standard error, and exits with the exit code for err.
+func Fatal(err error) {
+	PrintError(err)
+	os.Exit(ExitCode(err))
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+
+	"github.com/sorairolake/abcrypt-go/examples"
 )
 
 type options struct {
@@ -19,11 +21,12 @@ type options struct {
 var opt options
 
 func init() {
-	flag.BoolVar(&opt.json, "json", false, "Output the encryption parameters as JSON")
+	flag.BoolVar(&opt.json, "json", false, "Output the header of each file as a line of JSON (JSON Lines)")
+	flag.BoolVar(&examples.JSONErrors, "json-errors", false, "Print errors as JSON objects")
 	flag.BoolVar(&opt.version, "version", false, "Print version number")
 
 	flag.Usage = func() {
-		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] [FILE]\n", os.Args[0]); err != nil {
+		if _, err := fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [OPTIONS] [FILE|DIR]...\n", os.Args[0]); err != nil {
 			log.Fatal(err)
 		}
 
@@ -2,21 +2,30 @@
 //
 // SPDX-License-Identifier: Apache-2.0 OR MIT
 
-// Info is an example of reading the Argon2 parameters from a file.
+// Info is an example of reading the header from encrypted files.
+//
+// It prints the format version, the Argon2 type, version and parameters, the
+// salt, the nonce and the header MAC in hexadecimal, the payload length and
+// the memory required for the key derivation. A directory is searched
+// recursively for files with the ".abcrypt" extension.
 //
 // Usage:
 //
-//	info [OPTIONS] [FILE]
+//	info [OPTIONS] [FILE|DIR]...
 //
 // Arguments:
 //
-//	[FILE]
-//		Input file.
+//	[FILE|DIR]...
+//		Input files or directories.
 //
 // Options:
 //
 //	-json
-//		Output the encryption parameters as JSON.
+//		Output the header of each file as a line of JSON (JSON Lines).
+//	-json-errors
+//		Print errors as JSON objects.
 //	-version
 //		Print version number.
+//
+// The exit status is described in the examples package.
 package main
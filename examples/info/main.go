@@ -6,16 +6,25 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
-	"log"
+	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/sorairolake/abcrypt-go"
 	"github.com/sorairolake/abcrypt-go/examples"
 )
 
+type info struct {
+	File string `json:"file,omitempty"`
+	*abcrypt.Header
+	PayloadLength int64  `json:"payloadLength"`
+	KDFMemory     uint64 `json:"kdfMemory"`
+}
+
 func main() {
 	flag.Parse()
 	args := flag.Args()
@@ -25,43 +34,124 @@ func main() {
 		os.Exit(0)
 	}
 
-	var ciphertext []byte
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	var names []string
+
+	for _, arg := range args {
+		if fi, err := os.Stat(arg); err != nil || !fi.IsDir() {
+			names = append(names, arg)
+
+			continue
+		}
+
+		// Search a directory for the encrypted files.
+		err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type().IsRegular() && filepath.Ext(path) == abcrypt.FileExtension {
+				names = append(names, path)
+			}
+
+			return err
+		})
+		if err != nil {
+			examples.Fatal(err)
+		}
+	}
+
+	failed := false
+
+	for _, name := range names {
+		i, err := readInfo(name)
+		if err != nil {
+			// A single input exits with the exit code for its error.
+			if len(names) == 1 {
+				examples.Fatal(err)
+			}
+
+			examples.PrintError(fmt.Errorf("%v: %w", name, err))
+
+			failed = true
+
+			continue
+		}
+
+		if opt.json {
+			// Each file is output as a line of JSON (JSON Lines).
+			json, err := json.Marshal(i)
+			if err != nil {
+				examples.Fatal(err)
+			}
+			fmt.Println(string(json))
+		} else {
+			if i.File != "" {
+				fmt.Printf("File: %v\n", i.File)
+			}
+
+			fmt.Printf("Format version: %v\n", i.Version)
+			fmt.Printf("Argon2 type: %v\n", i.Argon2Type)
+			fmt.Printf("Argon2 version: %v\n", i.Argon2Version)
+			fmt.Printf("Parameters used: memoryCost = %v; timeCost = %v; parallelism = %v;\n", i.MemoryCost, i.TimeCost, i.Parallelism)
+			fmt.Printf("Salt: %v\n", i.Salt)
+			fmt.Printf("Nonce: %v\n", i.Nonce)
+			fmt.Printf("Header MAC: %v\n", i.MAC)
+			fmt.Printf("Payload length: %v bytes\n", i.PayloadLength)
+			fmt.Printf("KDF memory: %v bytes\n", i.KDFMemory)
+		}
+	}
+
+	if failed {
+		os.Exit(examples.ExitUsage)
+	}
+}
 
-	switch flag.NArg() {
-	case 0:
-		b, err := io.ReadAll(os.Stdin)
+// readInfo reads only the header of the file, and takes the payload length
+// from the file size.
+func readInfo(name string) (*info, error) {
+	r := os.Stdin
+	size := int64(-1)
+
+	if name != "-" {
+		f, err := os.Open(name)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
+		defer f.Close()
 
-		ciphertext = b
-	case 1:
-		b, err := os.ReadFile(args[0])
+		fi, err := f.Stat()
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
-		ciphertext = b
-	default:
-		flag.Usage()
-		os.Exit(1)
+		r, size = f, fi.Size()
+	}
+
+	buf := make([]byte, abcrypt.HeaderSize+abcrypt.TagSize)
+
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
 	}
 
-	params, err := abcrypt.NewParams(ciphertext)
+	header, err := abcrypt.NewHeader(buf[:n])
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	if opt.json {
-		json, err := json.Marshal(params)
+	if size < 0 {
+		m, err := io.Copy(io.Discard, r)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		fmt.Println(string(json))
-	} else {
-		m := params.MemoryCost
-		t := params.TimeCost
-		p := params.Parallelism
-		fmt.Printf("Parameters used: memoryCost = %v; timeCost = %v; parallelism = %v;\n", m, t, p)
+
+		size = int64(n) + m
+	}
+
+	i := info{Header: header, PayloadLength: size - abcrypt.HeaderSize - abcrypt.TagSize, KDFMemory: header.Params().MemoryBytes()}
+	if name != "-" {
+		i.File = name
 	}
+
+	return &i, nil
 }
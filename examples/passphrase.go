@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package examples
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"golang.org/x/term"
+)
+
+// ReadPassphrase writes prompt to standard error and reads a passphrase
+// without echo.
+//
+// If standard input is not a terminal, such as when it is the input data of a
+// pipeline, the passphrase is read from the controlling terminal instead.
+func ReadPassphrase(prompt string) ([]byte, error) {
+	tty := os.Stdin
+
+	if !term.IsTerminal(int(tty.Fd())) {
+		name := "/dev/tty"
+		if runtime.GOOS == "windows" {
+			name = "CONIN$"
+		}
+
+		f, err := os.OpenFile(name, os.O_RDWR, 0)
+		if err != nil {
+			return nil, fmt.Errorf("could not open the terminal: %w", err)
+		}
+		defer f.Close()
+
+		tty = f
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+
+	return term.ReadPassword(int(tty.Fd()))
+}
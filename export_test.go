@@ -17,8 +17,10 @@ const (
 const Argon2d = argon2d
 
 const (
-	Version0x10 = version0x10
-	Version0x13 = version0x13
+	Version0x10 = Argon2Version0x10
+	Version0x13 = Argon2Version0x13
 )
 
 const SaltSize = saltSize
+
+const MaxCachedKeys = maxCachedKeys
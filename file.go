@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// defaultEncryptedFileMode is the permission bits of the output file of
+	// [EncryptFile]. The encrypted data is not secret.
+	defaultEncryptedFileMode fs.FileMode = 0o644
+
+	// defaultDecryptedFileMode is the permission bits of the output file of
+	// [DecryptFile].
+	defaultDecryptedFileMode fs.FileMode = 0o600
+)
+
+// EncryptFile encrypts the data read from r and writes the encrypted data to
+// the named file.
+//
+// The data is streamed with [Writer] into a temporary file in the same
+// directory, which is synced and renamed to name only after everything has
+// been written, so name is never left partially written. The file is created
+// with mode 0644 unless [WithFileMode] is given, less the umask.
+//
+// If the file already exists, an error wrapping [fs.ErrExist] is returned
+// unless [WithOverwrite] is given. With [WithVerify], the temporary file is
+// decrypted and compared with the data read from r before renaming. The
+// other options are the same as [NewEncryptorWithOptions].
+func EncryptFile(ctx context.Context, name string, r io.Reader, passphrase []byte, opts ...Option) error {
+	o := newOptions(opts)
+
+	if err := checkOutput(name, o); err != nil {
+		return err
+	}
+
+	var (
+		w   *Writer
+		sum hash.Hash
+	)
+
+	write := func(out io.Writer) error {
+		var err error
+
+		w, err = newWriter(ctx, out, passphrase, o)
+		if err != nil {
+			return err
+		}
+
+		if o.verify {
+			sum = newSum()
+			r = io.TeeReader(r, sum)
+		}
+
+		if _, err := io.Copy(w, r); err != nil {
+			return err
+		}
+
+		return w.Close()
+	}
+
+	verify := func(f io.Reader) error {
+		header, buf, err := readHeader(f)
+		if err != nil {
+			return err
+		}
+
+		if err := header.verifyMAC(w.dk.mac[:], buf[84:HeaderSize]); err != nil || header.asBytes() != w.header.asBytes() {
+			return ErrVerificationFailed
+		}
+
+		// The derived key is reused, so Argon2 is not run again.
+		plaintext := newSum()
+		if _, err := io.Copy(plaintext, newReaderWithKey(f, header, w.dk, buf, NopObserver{})); err != nil {
+			return errors.Join(ErrVerificationFailed, err)
+		}
+
+		return compareSums(sum, plaintext)
+	}
+
+	return writeFile(name, o, defaultEncryptedFileMode, write, verify)
+}
+
+// DecryptFile decrypts the encrypted data read from r and writes the
+// plaintext to the named file.
+//
+// The data is streamed with [Reader] into a temporary file in the same
+// directory, which is synced and renamed to name only after the MAC of the
+// ciphertext has been verified, so neither a partially written nor an
+// unauthenticated plaintext is left at name. The file is created with mode
+// 0600 unless [WithFileMode] is given, less the umask.
+//
+// If the file already exists, an error wrapping [fs.ErrExist] is returned
+// unless [WithOverwrite] is given. With [WithVerify], the temporary file is
+// read back and compared with the decrypted data before renaming. The other
+// options are the same as [NewDecryptorWithOptions].
+func DecryptFile(ctx context.Context, name string, r io.Reader, passphrase []byte, opts ...Option) error {
+	o := newOptions(opts)
+
+	if err := checkOutput(name, o); err != nil {
+		return err
+	}
+
+	var sum hash.Hash
+
+	write := func(out io.Writer) error {
+		sr, err := newReader(ctx, r, passphrase, o)
+		if err != nil {
+			return err
+		}
+
+		if o.verify {
+			sum = newSum()
+			out = io.MultiWriter(out, sum)
+		}
+
+		_, err = io.Copy(out, sr)
+
+		return err
+	}
+
+	verify := func(f io.Reader) error {
+		written := newSum()
+		if _, err := io.Copy(written, f); err != nil {
+			return errors.Join(ErrVerificationFailed, err)
+		}
+
+		return compareSums(sum, written)
+	}
+
+	return writeFile(name, o, defaultDecryptedFileMode, write, verify)
+}
+
+// checkOutput fails early if the output file exists, so the key derivation
+// is not wasted.
+func checkOutput(name string, o *options) error {
+	if o.overwrite {
+		return nil
+	}
+
+	if _, err := os.Lstat(name); err == nil {
+		return &fs.PathError{Op: "create", Path: name, Err: fs.ErrExist}
+	}
+
+	return nil
+}
+
+// writeFile writes the named file atomically. write is called with the
+// temporary file, and verify is called with the temporary file read from the
+// beginning if [WithVerify] is given.
+func writeFile(name string, o *options, perm fs.FileMode, write func(io.Writer) error, verify func(io.Reader) error) (err error) {
+	if o.fileMode != nil {
+		perm = *o.fileMode
+	}
+
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+
+	f, err := createTemp(dir, base, perm)
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	bw := bufio.NewWriterSize(f, streamChunkSize)
+
+	if err := write(bw); err != nil {
+		return err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	if o.verify {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		if err := verify(bufio.NewReaderSize(f, streamChunkSize)); err != nil {
+			return err
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := rename(tmp, name, o.overwrite); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+
+	return nil
+}
+
+// createTemp creates a new temporary file in the directory for the named file.
+// Unlike [os.CreateTemp], the file is created with perm, so the umask applies.
+func createTemp(dir, base string, perm fs.FileMode) (*os.File, error) {
+	for range 10000 {
+		name := filepath.Join(dir, "."+base+"."+strconv.FormatUint(uint64(rand.Uint32()), 10)+".tmp")
+
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+
+		return f, err
+	}
+
+	return nil, &fs.PathError{Op: "createtemp", Path: filepath.Join(dir, "."+base+".*.tmp"), Err: fs.ErrExist}
+}
+
+// rename moves the temporary file to name. Unless overwrite is true, this
+// fails if name exists, even if it was created after checkOutput.
+func rename(tmp, name string, overwrite bool) error {
+	if overwrite {
+		return os.Rename(tmp, name)
+	}
+
+	// A hard link fails if the destination exists, which makes the check
+	// atomic. If the file system does not support hard links, fall back to
+	// checking before renaming.
+	err := os.Link(tmp, name)
+	if err == nil {
+		return os.Remove(tmp)
+	}
+
+	if errors.Is(err, fs.ErrExist) {
+		return &fs.PathError{Op: "create", Path: name, Err: fs.ErrExist}
+	}
+
+	if _, err := os.Lstat(name); err == nil {
+		return &fs.PathError{Op: "create", Path: name, Err: fs.ErrExist}
+	}
+
+	return os.Rename(tmp, name)
+}
+
+// syncDir makes the rename durable. Directories cannot be synced on some
+// platforms such as Windows, so this is done on a best-effort basis.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+func newSum() hash.Hash {
+	h, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return h
+}
+
+func compareSums(expected, actual hash.Hash) error {
+	if subtle.ConstantTimeCompare(expected.Sum(nil), actual.Sum(nil)) != 1 {
+		return ErrVerificationFailed
+	}
+
+	return nil
+}
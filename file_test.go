@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// checkNoTempFiles reports the temporary files left in dir.
+func checkNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("unexpected temporary file `%v`", e.Name())
+		}
+	}
+}
+
+func checkMode(t *testing.T, name string, expected fs.FileMode) {
+	t.Helper()
+
+	// Windows supports only the read-only attribute.
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := info.Mode().Perm(); mode != expected {
+		t.Errorf("expected mode `%v`, got `%v`", expected, mode)
+	}
+}
+
+func TestEncryptFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	encrypted := filepath.Join(dir, "data.txt.abcrypt")
+	decrypted := filepath.Join(dir, "data.txt")
+
+	if err := abcrypt.EncryptFile(context.Background(), encrypted, strings.NewReader(data), []byte(passphrase), append(streamOpts, abcrypt.WithVerify())...); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMode(t, encrypted, 0o644)
+
+	ciphertext, err := os.ReadFile(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := abcrypt.DecryptFile(context.Background(), decrypted, bytes.NewReader(ciphertext), []byte(passphrase), abcrypt.WithVerify()); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMode(t, decrypted, 0o600)
+
+	plaintext, err := os.ReadFile(decrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Errorf("expected plaintext `%v`, got `%s`", data, plaintext)
+	}
+
+	checkNoTempFiles(t, dir)
+}
+
+func TestEncryptFileWithFileMode(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "data.txt.abcrypt")
+
+	if err := abcrypt.EncryptFile(context.Background(), name, strings.NewReader(data), []byte(passphrase), append(streamOpts, abcrypt.WithFileMode(0o640))...); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMode(t, name, 0o640)
+}
+
+func TestEncryptFileWithUmask(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// The umask is process-wide, so compare with a file created in the same
+	// way instead of changing it.
+	f, err := os.OpenFile(filepath.Join(dir, "reference"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(dir, "data.txt.abcrypt")
+
+	if err := abcrypt.EncryptFile(context.Background(), name, strings.NewReader(data), []byte(passphrase), append(streamOpts, abcrypt.WithFileMode(0o666))...); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMode(t, name, info.Mode().Perm())
+}
+
+func TestEncryptFileExists(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "data.txt.abcrypt")
+
+	if err := os.WriteFile(name, []byte("existing"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := abcrypt.EncryptFile(context.Background(), name, strings.NewReader(data), []byte(passphrase), streamOpts...); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("expected error `%v`, got `%v`", fs.ErrExist, err)
+	}
+
+	if b, _ := os.ReadFile(name); string(b) != "existing" {
+		t.Errorf("expected contents `%v`, got `%s`", "existing", b)
+	}
+
+	if err := abcrypt.EncryptFile(context.Background(), name, strings.NewReader(data), []byte(passphrase), append(streamOpts, abcrypt.WithOverwrite())...); err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := abcrypt.Decrypt(ciphertext, []byte(passphrase)); err != nil {
+		t.Error(err)
+	}
+
+	checkNoTempFiles(t, dir)
+}
+
+func TestDecryptFileWithInvalidMAC(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "data.txt")
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext[len(ciphertext)-1] ^= 1
+
+	err = abcrypt.DecryptFile(context.Background(), name, bytes.NewReader(ciphertext), []byte(passphrase))
+
+	var invalidMACErr *abcrypt.InvalidMACError
+	if !errors.As(err, &invalidMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", invalidMACErr, err)
+	}
+
+	if _, err := os.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected error `%v`, got `%v`", fs.ErrNotExist, err)
+	}
+
+	checkNoTempFiles(t, dir)
+}
+
+func TestDecryptFileWithInvalidHeaderMAC(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "data.txt")
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = abcrypt.DecryptFile(context.Background(), name, bytes.NewReader(ciphertext), []byte("password"))
+
+	var headerMACErr *abcrypt.InvalidHeaderMACError
+	if !errors.As(err, &headerMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", headerMACErr, err)
+	}
+
+	checkNoTempFiles(t, dir)
+}
@@ -44,15 +44,15 @@ const (
 	Argon2id
 )
 
-// argon2Version is a type that represents the Argon2 version.
-type argon2Version uint32
+// Argon2Version is a type that represents the Argon2 version.
+type Argon2Version uint32
 
 const (
-	// version0x10 indicates version 0x10.
-	version0x10 argon2Version = 0x10
+	// Argon2Version0x10 indicates version 0x10.
+	Argon2Version0x10 Argon2Version = 0x10
 
-	// version0x13 indicates version 0x13.
-	version0x13 argon2Version = 0x13
+	// Argon2Version0x13 indicates version 0x13.
+	Argon2Version0x13 Argon2Version = 0x13
 )
 
 const saltSize = 32
@@ -61,7 +61,7 @@ type header struct {
 	magicNumber   [magicNumberSize]byte
 	version       version
 	argon2Type    Argon2Type
-	argon2Version argon2Version
+	argon2Version Argon2Version
 	memoryCost    uint32
 	timeCost      uint32
 	parallelism   uint32
@@ -70,7 +70,7 @@ type header struct {
 	mac           [blake2b.Size]byte
 }
 
-func newHeader(argon2Type Argon2Type, argon2Version argon2Version, memoryCost, timeCost, parallelism uint32) *header {
+func newHeader(argon2Type Argon2Type, argon2Version Argon2Version, memoryCost, timeCost, parallelism uint32) *header {
 	var header header
 
 	header.magicNumber = [magicNumberSize]byte([]byte(magicNumber))
@@ -84,7 +84,7 @@ func newHeader(argon2Type Argon2Type, argon2Version argon2Version, memoryCost, t
 	}
 
 	switch argon2Version {
-	case version0x10, version0x13:
+	case Argon2Version0x10, Argon2Version0x13:
 		header.argon2Version = argon2Version
 	default:
 		panic("abcrypt: invalid Argon2 version")
@@ -140,8 +140,8 @@ func parse(data []byte) (*header, error) {
 		return nil, &InvalidArgon2TypeError{uint32(t)}
 	}
 
-	switch v := argon2Version(binary.LittleEndian.Uint32(data[12:16])); v {
-	case version0x10, version0x13:
+	switch v := Argon2Version(binary.LittleEndian.Uint32(data[12:16])); v {
+	case Argon2Version0x10, Argon2Version0x13:
 		header.argon2Version = v
 	default:
 		return nil, &InvalidArgon2VersionError{uint32(v)}
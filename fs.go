@@ -0,0 +1,411 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileExtension is the file name extension of the abcrypt encrypted files.
+const FileExtension = ".abcrypt"
+
+// FS represents a file system which transparently decrypts the abcrypt
+// encrypted files in the underlying file system.
+//
+// A regular file named "foo.json.abcrypt" is presented as "foo.json" and is
+// decrypted when it is opened or read. If both "foo.json" and
+// "foo.json.abcrypt" exist, the encrypted file takes precedence. Other files
+// and directories are passed through unchanged.
+//
+// The derived keys are cached for each combination of the salt and the Argon2
+// parameters, so reopening the same encrypted file does not run Argon2 again.
+// Up to 128 keys are cached, and the least recently used one is evicted
+// beyond that. The decrypted data is not cached.
+//
+// FS implements [fs.FS], [fs.ReadFileFS] and [fs.ReadDirFS], and is safe for
+// concurrent use.
+type FS struct {
+	base       fs.FS
+	passphrase []byte
+	opts       *options
+
+	mu    sync.Mutex
+	keys  map[[60]byte]*list.Element
+	order list.List
+}
+
+// maxCachedKeys is the maximum number of the derived keys cached by [FS].
+const maxCachedKeys = 128
+
+// cachedKey represents a derived key in the cache of [FS]. The most recently
+// used one is at the front of the list.
+type cachedKey struct {
+	id [60]byte
+	dk *derivedKey
+}
+
+// NewFS creates a new [FS] which decrypts the files in base with the given
+// passphrase.
+//
+// The options except for [WithKeyDeriver], [WithMemoryBudget],
+// [WithMemoryBudgetNoWait], [WithObserver], [WithKeyfile] and [WithPepper]
+// are ignored. If the passphrase is held by a [KeyDeriver] such as an agent,
+// passphrase may be nil.
+func NewFS(base fs.FS, passphrase []byte, opts ...Option) *FS {
+	fsys := FS{
+		base:       base,
+		passphrase: bytes.Clone(passphrase),
+		opts:       newOptions(opts),
+		keys:       make(map[[60]byte]*list.Element),
+	}
+
+	return &fsys
+}
+
+// Open opens the named file.
+//
+// This implements [fs.FS].
+func (fsys *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	encrypted, err := fsys.isEncrypted(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	if encrypted {
+		info, err := fs.Stat(fsys.base, name+FileExtension)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+
+		plaintext, err := fsys.readEncrypted(name)
+		if err != nil {
+			return nil, err
+		}
+
+		f := decryptedFile{bytes.NewReader(plaintext), &fileInfo{info, name, int64(len(plaintext))}}
+
+		return &f, nil
+	}
+
+	f, err := fsys.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return f, nil
+	}
+
+	d := dirFile{File: f, fsys: fsys, name: name}
+
+	return &d, nil
+}
+
+// ReadFile reads the named file and returns its contents. If the file is
+// encrypted, the decrypted contents are returned.
+//
+// This implements [fs.ReadFileFS].
+func (fsys *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+
+	encrypted, err := fsys.isEncrypted(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+
+	if encrypted {
+		return fsys.readEncrypted(name)
+	}
+
+	return fs.ReadFile(fsys.base, name)
+}
+
+// ReadDir reads the named directory and returns a list of directory entries
+// sorted by filename.
+//
+// The encrypted files are listed without [FileExtension], and their sizes are
+// the sizes of the decrypted data.
+//
+// This implements [fs.ReadDirFS].
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(fsys.base, name)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]fs.DirEntry, 0, len(entries))
+	seen := make(map[string]int, len(entries))
+
+	for _, e := range entries {
+		entryName, ok := decryptedName(e.Name())
+		if !ok || !e.Type().IsRegular() {
+			if _, dup := seen[e.Name()]; !dup {
+				seen[e.Name()] = len(list)
+				list = append(list, e)
+			}
+
+			continue
+		}
+
+		entry := dirEntry{e, entryName}
+		if i, dup := seen[entryName]; dup {
+			list[i] = &entry
+
+			continue
+		}
+
+		seen[entryName] = len(list)
+		list = append(list, &entry)
+	}
+
+	slices.SortFunc(list, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return list, nil
+}
+
+// isEncrypted reports whether name refers to an encrypted file. Any other
+// regular file whose name has [FileExtension] is hidden and reported as not
+// existing.
+func (fsys *FS) isEncrypted(name string) (bool, error) {
+	if name != "." {
+		info, err := fs.Stat(fsys.base, name+FileExtension)
+
+		switch {
+		case err == nil && info.Mode().IsRegular():
+			return true, nil
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return false, err
+		}
+	}
+
+	if _, ok := decryptedName(name); ok {
+		info, err := fs.Stat(fsys.base, name)
+		if err == nil && info.Mode().IsRegular() {
+			return false, fs.ErrNotExist
+		}
+	}
+
+	return false, nil
+}
+
+func (fsys *FS) readEncrypted(name string) ([]byte, error) {
+	ciphertext, err := fs.ReadFile(fsys.base, name+FileExtension)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := fsys.newDecryptor(ciphertext)
+	if err != nil {
+		return nil, &fs.PathError{Op: "decrypt", Path: name, Err: err}
+	}
+
+	plaintext, err := d.Decrypt()
+	if err != nil {
+		return nil, &fs.PathError{Op: "decrypt", Path: name, Err: err}
+	}
+
+	return plaintext, nil
+}
+
+func (fsys *FS) newDecryptor(ciphertext []byte) (*Decryptor, error) {
+	header, err := parse(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first 60 bytes of the header are the inputs of the key derivation.
+	id := [60]byte(ciphertext[:60])
+
+	dk, ok := fsys.cachedKey(id)
+	if !ok {
+		dk, _, err = unlock(context.Background(), fsys.opts, header, ciphertext, fsys.passphrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := newDecryptor(fsys.opts, header, dk, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the keys which authenticated the header are cached, so a wrong
+	// passphrase is not remembered.
+	if !ok {
+		fsys.cacheKey(id, dk)
+	}
+
+	return d, nil
+}
+
+func (fsys *FS) cachedKey(id [60]byte) (*derivedKey, bool) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	e, ok := fsys.keys[id]
+	if !ok {
+		return nil, false
+	}
+
+	fsys.order.MoveToFront(e)
+
+	return e.Value.(cachedKey).dk, true
+}
+
+func (fsys *FS) cacheKey(id [60]byte, dk *derivedKey) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	// The same key may have been derived concurrently.
+	if e, ok := fsys.keys[id]; ok {
+		fsys.order.MoveToFront(e)
+
+		return
+	}
+
+	fsys.keys[id] = fsys.order.PushFront(cachedKey{id, dk})
+
+	// The evicted key is not cleared, since a decryptor may still use it.
+	if fsys.order.Len() > maxCachedKeys {
+		e := fsys.order.Back()
+		fsys.order.Remove(e)
+		delete(fsys.keys, e.Value.(cachedKey).id)
+	}
+}
+
+func decryptedName(name string) (string, bool) {
+	base, ok := strings.CutSuffix(name, FileExtension)
+	if !ok || base == "" || strings.HasSuffix(base, "/") {
+		return name, false
+	}
+
+	return base, true
+}
+
+func decryptedSize(size int64) int64 {
+	return max(size-HeaderSize-TagSize, 0)
+}
+
+type decryptedFile struct {
+	*bytes.Reader
+	info *fileInfo
+}
+
+func (f *decryptedFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *decryptedFile) Close() error {
+	return nil
+}
+
+type fileInfo struct {
+	info fs.FileInfo
+	name string
+	size int64
+}
+
+func (fi *fileInfo) Name() string {
+	return fi.name[strings.LastIndexByte(fi.name, '/')+1:]
+}
+
+func (fi *fileInfo) Size() int64 {
+	return fi.size
+}
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	return fi.info.Mode()
+}
+
+func (fi *fileInfo) ModTime() time.Time {
+	return fi.info.ModTime()
+}
+
+func (fi *fileInfo) IsDir() bool {
+	return fi.info.IsDir()
+}
+
+func (fi *fileInfo) Sys() any {
+	return fi.info.Sys()
+}
+
+type dirEntry struct {
+	fs.DirEntry
+	name string
+}
+
+func (e *dirEntry) Name() string {
+	return e.name
+}
+
+func (e *dirEntry) Info() (fs.FileInfo, error) {
+	info, err := e.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileInfo{info, e.name, decryptedSize(info.Size())}, nil
+}
+
+type dirFile struct {
+	fs.File
+	fsys    *FS
+	name    string
+	entries []fs.DirEntry
+	loaded  bool
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.loaded {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+
+		d.entries = entries
+		d.loaded = true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+
+	return entries, nil
+}
@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+func newTestFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+
+	encrypt := func(s string) *fstest.MapFile {
+		ciphertext := abcrypt.NewEncryptorWithParams([]byte(s), []byte(passphrase), 32, 3, 4).Encrypt()
+
+		return &fstest.MapFile{Data: ciphertext, Mode: 0o600}
+	}
+
+	return fstest.MapFS{
+		"config.json.abcrypt":          encrypt(`{"name":"abcrypt"}`),
+		"templates/hello.tmpl.abcrypt": encrypt(`Hello, {{.}}!`),
+		"templates/bye.tmpl":           {Data: []byte(`Bye, {{.}}!`)},
+		"plain.txt":                    {Data: []byte(data)},
+		"shadowed.txt":                 {Data: []byte("plaintext")},
+		"shadowed.txt.abcrypt":         encrypt("ciphertext"),
+		"templates/empty.abcrypt":      encrypt(""),
+	}
+}
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := abcrypt.NewFS(newTestFS(t), []byte(passphrase))
+
+	if err := fstest.TestFS(fsys, "config.json", "plain.txt", "shadowed.txt", "templates/hello.tmpl", "templates/bye.tmpl", "templates/empty"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSReadFile(t *testing.T) {
+	t.Parallel()
+
+	fsys := abcrypt.NewFS(newTestFS(t), []byte(passphrase))
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"config.json", `{"name":"abcrypt"}`},
+		{"plain.txt", data},
+		{"shadowed.txt", "ciphertext"},
+		{"templates/empty", ""},
+	}
+
+	for _, tt := range tests {
+		contents, err := fsys.ReadFile(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(contents) != tt.expected {
+			t.Errorf("expected contents of `%v` `%v`, got `%v`", tt.name, tt.expected, string(contents))
+		}
+	}
+
+	if _, err := fsys.ReadFile("config.json.abcrypt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected error `%v`, got `%v`", fs.ErrNotExist, err)
+	}
+}
+
+func TestFSReadDir(t *testing.T) {
+	t.Parallel()
+
+	fsys := abcrypt.NewFS(newTestFS(t), []byte(passphrase))
+
+	entries, err := fsys.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	expected := "config.json plain.txt shadowed.txt templates"
+	if s := strings.Join(names, " "); s != expected {
+		t.Errorf("expected entries `%v`, got `%v`", expected, s)
+	}
+
+	info, err := entries[0].Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size := info.Size(); size != int64(len(`{"name":"abcrypt"}`)) {
+		t.Errorf("expected size `%v`, got `%v`", len(`{"name":"abcrypt"}`), size)
+	}
+}
+
+func TestFSParseTemplates(t *testing.T) {
+	t.Parallel()
+
+	fsys := abcrypt.NewFS(newTestFS(t), []byte(passphrase))
+
+	tmpl, err := template.ParseFS(fsys, "templates/*.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.ExecuteTemplate(&b, "hello.tmpl", "world"); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := b.String(); s != "Hello, world!" {
+		t.Errorf("expected output `%v`, got `%v`", "Hello, world!", s)
+	}
+}
+
+func TestFSCachesDerivedKeys(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	deriver := keyDeriverFunc(func(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+		calls.Add(1)
+
+		return abcrypt.Argon2KeyDeriver{}.DeriveKey(ctx, passphrase, salt, argon2Type, params)
+	})
+
+	fsys := abcrypt.NewFS(newTestFS(t), []byte(passphrase), abcrypt.WithKeyDeriver(deriver))
+
+	for range 3 {
+		if _, err := fsys.ReadFile("config.json"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected number of key derivations `%v`, got `%v`", 1, n)
+	}
+}
+
+func TestFSEvictsDerivedKeys(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	deriver := keyDeriverFunc(func(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+		calls.Add(1)
+
+		return abcrypt.Argon2KeyDeriver{}.DeriveKey(ctx, passphrase, salt, argon2Type, params)
+	})
+
+	base := make(fstest.MapFS)
+	names := make([]string, abcrypt.MaxCachedKeys+1)
+
+	for i := range names {
+		names[i] = fmt.Sprintf("%v.txt", i)
+		ciphertext := abcrypt.NewEncryptorWithParams([]byte(data), []byte(passphrase), 32, 3, 4).Encrypt()
+		base[names[i]+abcrypt.FileExtension] = &fstest.MapFile{Data: ciphertext, Mode: 0o600}
+	}
+
+	fsys := abcrypt.NewFS(base, []byte(passphrase), abcrypt.WithKeyDeriver(deriver))
+
+	for _, name := range names {
+		if _, err := fsys.ReadFile(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The most recently used key is kept, and the least recently used one is
+	// evicted.
+	for _, name := range []string{names[len(names)-1], names[0]} {
+		if _, err := fsys.ReadFile(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected, n := int32(len(names)+1), calls.Load(); n != expected {
+		t.Errorf("expected number of key derivations `%v`, got `%v`", expected, n)
+	}
+}
+
+func TestFSWithInvalidPassphrase(t *testing.T) {
+	t.Parallel()
+
+	fsys := abcrypt.NewFS(newTestFS(t), []byte("password"))
+
+	_, err := fsys.Open("config.json")
+
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) {
+		t.Fatalf("expected error type `%T`, got `%T`", pathErr, err)
+	}
+
+	var headerMACErr *abcrypt.InvalidHeaderMACError
+	if !errors.As(err, &headerMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", headerMACErr, pathErr.Err)
+	}
+
+	if _, err := fsys.ReadFile("plain.txt"); err != nil {
+		t.Errorf("expected no error, got `%v`", err)
+	}
+}
@@ -4,10 +4,11 @@
 
 module github.com/sorairolake/abcrypt-go
 
-go 1.24.1
+go 1.25.0
 
 require (
 	golang.org/x/crypto v0.36.0
+	golang.org/x/sys v0.31.0
 	golang.org/x/term v0.30.0
 )
 
@@ -196,7 +197,6 @@ require (
 	golang.org/x/net v0.37.0 // indirect
 	golang.org/x/pkgsite v0.0.0-20250321205054-d037ac96d503 // indirect
 	golang.org/x/sync v0.12.0 // indirect
-	golang.org/x/sys v0.31.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 	golang.org/x/tools v0.31.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"bytes"
+	"encoding/hex"
+)
+
+// HexBytes represents a byte sequence which is encoded in hexadecimal in text
+// formats such as JSON.
+type HexBytes []byte
+
+// String returns the byte sequence in lowercase hexadecimal.
+func (b HexBytes) String() string {
+	return hex.EncodeToString(b)
+}
+
+// MarshalText returns the byte sequence in lowercase hexadecimal.
+//
+// This implements [encoding.TextMarshaler].
+func (b HexBytes) MarshalText() ([]byte, error) {
+	return hex.AppendEncode(nil, b), nil
+}
+
+// UnmarshalText parses the byte sequence in hexadecimal.
+//
+// This implements [encoding.TextUnmarshaler].
+func (b *HexBytes) UnmarshalText(text []byte) error {
+	decoded, err := hex.AppendDecode(nil, text)
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+
+	return nil
+}
+
+// Header represents the header of the encrypted data.
+//
+// The JSON encoding uses the names of the Argon2 type and the Argon2 version,
+// and the salt, the nonce and the MAC are encoded in hexadecimal.
+type Header struct {
+	// Version represents the version number of the format.
+	Version byte `json:"version"`
+
+	// Argon2Type represents the Argon2 type.
+	Argon2Type Argon2Type `json:"argon2Type"`
+
+	// Argon2Version represents the Argon2 version.
+	Argon2Version Argon2Version `json:"argon2Version"`
+
+	// MemoryCost represents memory size in KiB.
+	MemoryCost uint32 `json:"memoryCost"`
+
+	// TimeCost represents the number of iterations.
+	TimeCost uint32 `json:"timeCost"`
+
+	// Parallelism represents the degree of parallelism.
+	Parallelism uint32 `json:"parallelism"`
+
+	// Salt represents the salt for Argon2.
+	Salt HexBytes `json:"salt"`
+
+	// Nonce represents the nonce for XChaCha20-Poly1305.
+	Nonce HexBytes `json:"nonce"`
+
+	// MAC represents the BLAKE2b-512-MAC of the header.
+	MAC HexBytes `json:"mac"`
+}
+
+// NewHeader creates a new [Header] from the given ciphertext.
+//
+// This does not verify the MAC of the header. Only the first [HeaderSize] +
+// [TagSize] bytes of the ciphertext are needed.
+func NewHeader(ciphertext []byte) (*Header, error) {
+	header, err := parse(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	h := Header{
+		Version:       byte(header.version),
+		Argon2Type:    header.argon2Type,
+		Argon2Version: header.argon2Version,
+		MemoryCost:    header.memoryCost,
+		TimeCost:      header.timeCost,
+		Parallelism:   header.parallelism,
+		Salt:          HexBytes(header.salt[:]),
+		Nonce:         HexBytes(header.nonce[:]),
+		MAC:           HexBytes(bytes.Clone(ciphertext[84:HeaderSize])),
+	}
+
+	return &h, nil
+}
+
+// Params returns the Argon2 parameters of the header.
+func (h *Header) Params() Params {
+	return Params{h.MemoryCost, h.TimeCost, h.Parallelism}
+}
+
+// CheckKDF returns an [UnsupportedKDFError] if the key derivation with the
+// Argon2 type, the Argon2 version, the number of iterations or the degree of
+// parallelism of the header is not supported by this package.
+func (h *Header) CheckKDF() error {
+	return checkKDF(h.Argon2Type, h.Argon2Version, h.TimeCost, h.Parallelism)
+}
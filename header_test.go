@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+func TestHeader(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := os.ReadFile("testdata/v1/argon2i/v0x10/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := abcrypt.NewHeader(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if argon2Type := header.Argon2Type; argon2Type != abcrypt.Argon2i {
+		t.Errorf("expected Argon2 type `%v`, got `%v`", abcrypt.Argon2i, argon2Type)
+	}
+
+	if argon2Version := header.Argon2Version; argon2Version != abcrypt.Argon2Version0x10 {
+		t.Errorf("expected Argon2 version `%v`, got `%v`", abcrypt.Argon2Version0x10, argon2Version)
+	}
+
+	expected := abcrypt.Params{MemoryCost: 12288, TimeCost: 3, Parallelism: 1}
+	if params := header.Params(); params != expected {
+		t.Errorf("expected Argon2 parameters `%v`, got `%v`", expected, params)
+	}
+}
+
+func TestHeaderMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := abcrypt.NewHeader(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	json, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = `{"version":1,"argon2Type":"argon2id","argon2Version":"0x13","memoryCost":32,"timeCost":3,"parallelism":4,` +
+		`"salt":"fed2bb72f3e48cf38bcc6dc74f875a87b8dbac816cbf3fbc54e0156cbded9b3f",` +
+		`"nonce":"7c893795c81e7c8cd37e5a809c995b8a7e44ef1274f83924",` +
+		`"mac":"85300f8aa3805cbc4be641bb00e7bc03d63b5e9d5fb6134e0d0146f15c861b1020197b92800199a6e31478d2d2a30f07d221bc45233926108cfa4ab07036b519"}`
+	if string(json) != expected {
+		t.Errorf("expected JSON `%v`, got `%s`", expected, json)
+	}
+}
+
+func TestHeaderFields(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := abcrypt.NewHeader(ciphertext[:abcrypt.HeaderSize+abcrypt.TagSize])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := header.Version; v != 1 {
+		t.Errorf("expected version `%v`, got `%v`", 1, v)
+	}
+
+	if !bytes.Equal(header.Salt, ciphertext[28:60]) {
+		t.Errorf("unexpected salt `%v`", header.Salt)
+	}
+
+	if !bytes.Equal(header.Nonce, ciphertext[60:84]) {
+		t.Errorf("unexpected nonce `%v`", header.Nonce)
+	}
+
+	if !bytes.Equal(header.MAC, ciphertext[84:abcrypt.HeaderSize]) {
+		t.Errorf("unexpected MAC `%v`", header.MAC)
+	}
+
+	header.MAC[0] ^= 1
+
+	if header.MAC[0] == ciphertext[84] {
+		t.Error("expected MAC not to share the ciphertext")
+	}
+}
+
+func TestHeaderCheckKDF(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		supported bool
+	}{
+		{"testdata/v1/argon2d/v0x10/data.txt.abcrypt", false},
+		{"testdata/v1/argon2d/v0x13/data.txt.abcrypt", false},
+		{"testdata/v1/argon2i/v0x10/data.txt.abcrypt", false},
+		{"testdata/v1/argon2i/v0x13/data.txt.abcrypt", true},
+		{"testdata/v1/argon2id/v0x10/data.txt.abcrypt", false},
+		{"testdata/v1/argon2id/v0x13/data.txt.abcrypt", true},
+	} {
+		ciphertext, err := os.ReadFile(tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		header, err := abcrypt.NewHeader(ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var unsupportedKDFErr *abcrypt.UnsupportedKDFError
+		if err := header.CheckKDF(); (err == nil) != tc.supported || (err != nil && !errors.As(err, &unsupportedKDFErr)) {
+			t.Errorf("unexpected error for `%v`: %v", tc.name, err)
+		}
+	}
+
+	for _, params := range []abcrypt.Params{
+		{MemoryCost: 32, TimeCost: 3, Parallelism: 256},
+		{MemoryCost: 32, TimeCost: 0, Parallelism: 4},
+		{MemoryCost: 32, TimeCost: 3, Parallelism: 0},
+	} {
+		header := abcrypt.Header{Argon2Type: abcrypt.Argon2id, Argon2Version: abcrypt.Argon2Version0x13, TimeCost: params.TimeCost, Parallelism: params.Parallelism}
+		if err := header.CheckKDF(); err == nil {
+			t.Errorf("expected error for `%v`", params)
+		}
+	}
+}
+
+func TestHeaderUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := abcrypt.NewHeader(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded abcrypt.Header
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(decoded.Salt, header.Salt) || !bytes.Equal(decoded.Nonce, header.Nonce) || !bytes.Equal(decoded.MAC, header.MAC) {
+		t.Errorf("expected header `%+v`, got `%+v`", header, decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`{"salt":"xyz"}`), &decoded); err == nil {
+		t.Error("expected error for invalid hexadecimal")
+	}
+}
+
+func TestHexBytesString(t *testing.T) {
+	t.Parallel()
+
+	b := abcrypt.HexBytes{0x00, 0xab, 0xff}
+	if s := b.String(); s != "00abff" {
+		t.Errorf("expected string `%v`, got `%v`", "00abff", s)
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package cli contains the exit codes and the version number shared by the
+// commands and the sample applications.
+package cli
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// Exit codes of the commands.
+const (
+	// ExitSuccess indicates that the command succeeded.
+	ExitSuccess = 0
+
+	// ExitFailure indicates an error which has no specific exit code.
+	ExitFailure = 1
+
+	// ExitUsage indicates that the command line was invalid.
+	ExitUsage = 2
+
+	// ExitInvalidPassphrase indicates that the MAC of the header was invalid,
+	// that is, the passphrase or the keyfile was wrong, or the header was
+	// modified.
+	ExitInvalidPassphrase = 3
+
+	// ExitCorrupted indicates that the MAC of the ciphertext was invalid, that
+	// is, the payload was corrupted or truncated.
+	ExitCorrupted = 4
+
+	// ExitNotAbcrypt indicates that the data was not in the abcrypt encrypted
+	// data format.
+	ExitNotAbcrypt = 5
+
+	// ExitUnsupported indicates that the version of the format, the Argon2
+	// type or version, or the degree of parallelism was not supported.
+	ExitUnsupported = 6
+
+	// ExitIO indicates an error of reading or writing a file.
+	ExitIO = 7
+)
+
+// errorKinds are the names of the exit codes in [ErrorReport].
+var errorKinds = map[int]string{
+	ExitFailure:           "failure",
+	ExitUsage:             "usage",
+	ExitInvalidPassphrase: "invalid-passphrase",
+	ExitCorrupted:         "corrupted",
+	ExitNotAbcrypt:        "not-abcrypt",
+	ExitUnsupported:       "unsupported",
+	ExitIO:                "io",
+}
+
+// ExitCode returns the exit code for err, which is mapped from the error
+// types of the abcrypt package. It returns [ExitSuccess] if err is nil.
+//
+// If err wraps an error which has the ExitCode method, such as an error due to
+// the command line, its result is returned.
+func ExitCode(err error) int {
+	var (
+		coder         interface{ ExitCode() int }
+		headerMACErr  *abcrypt.InvalidHeaderMACError
+		macErr        *abcrypt.InvalidMACError
+		unsupported   *abcrypt.UnsupportedVersionError
+		unknown       *abcrypt.UnknownVersionError
+		argon2TypeErr *abcrypt.InvalidArgon2TypeError
+		argon2VerErr  *abcrypt.InvalidArgon2VersionError
+		kdfErr        *abcrypt.UnsupportedKDFError
+		pathErr       *fs.PathError
+		linkErr       *os.LinkError
+		errno         syscall.Errno
+	)
+
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.As(err, &coder):
+		return coder.ExitCode()
+	case errors.As(err, &headerMACErr):
+		return ExitInvalidPassphrase
+	case errors.As(err, &macErr):
+		return ExitCorrupted
+	case errors.Is(err, abcrypt.ErrInvalidMagicNumber), errors.Is(err, abcrypt.ErrInvalidLength):
+		return ExitNotAbcrypt
+	case errors.As(err, &unsupported), errors.As(err, &unknown), errors.As(err, &argon2TypeErr), errors.As(err, &argon2VerErr), errors.As(err, &kdfErr):
+		return ExitUnsupported
+	case errors.As(err, &pathErr), errors.As(err, &linkErr), errors.As(err, &errno), errors.Is(err, abcrypt.ErrVerificationFailed):
+		return ExitIO
+	default:
+		return ExitFailure
+	}
+}
+
+// ErrorReport represents a machine-readable error.
+type ErrorReport struct {
+	// Error represents the error message.
+	Error string `json:"error"`
+
+	// Kind represents the name of the exit code, which is one of "failure",
+	// "usage", "invalid-passphrase", "corrupted", "not-abcrypt",
+	// "unsupported" and "io".
+	Kind string `json:"kind"`
+
+	// Code represents the exit code.
+	Code int `json:"code"`
+}
+
+// NewErrorReport returns a new [ErrorReport] for err.
+func NewErrorReport(err error) *ErrorReport {
+	code := ExitCode(err)
+	r := ErrorReport{err.Error(), errorKinds[code], code}
+
+	return &r
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package cli_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/internal/cli"
+)
+
+type codeError struct{}
+
+func (codeError) Error() string {
+	return "code error"
+}
+
+func (codeError) ExitCode() int {
+	return cli.ExitUsage
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	_, pathErr := os.Open("nonexistent")
+
+	for _, tc := range []struct {
+		err      error
+		expected int
+	}{
+		{nil, cli.ExitSuccess},
+		{errors.New("error"), cli.ExitFailure},
+		{fmt.Errorf("wrapped: %w", codeError{}), cli.ExitUsage},
+		{&abcrypt.InvalidHeaderMACError{}, cli.ExitInvalidPassphrase},
+		{&abcrypt.InvalidMACError{Err: errors.New("error")}, cli.ExitCorrupted},
+		{abcrypt.ErrInvalidMagicNumber, cli.ExitNotAbcrypt},
+		{fmt.Errorf("wrapped: %w", abcrypt.ErrInvalidLength), cli.ExitNotAbcrypt},
+		{&abcrypt.UnsupportedVersionError{Version: 0}, cli.ExitUnsupported},
+		{&abcrypt.UnknownVersionError{Version: 2}, cli.ExitUnsupported},
+		{&abcrypt.InvalidArgon2TypeError{Variant: 3}, cli.ExitUnsupported},
+		{&abcrypt.InvalidArgon2VersionError{Version: 0x14}, cli.ExitUnsupported},
+		{&abcrypt.UnsupportedKDFError{Reason: "Argon2d is not supported"}, cli.ExitUnsupported},
+		{pathErr, cli.ExitIO},
+		{&fs.PathError{Op: "create", Path: "file", Err: fs.ErrExist}, cli.ExitIO},
+		{abcrypt.ErrVerificationFailed, cli.ExitIO},
+	} {
+		if code := cli.ExitCode(tc.err); code != tc.expected {
+			t.Errorf("expected exit code of `%v` `%v`, got `%v`", tc.err, tc.expected, code)
+		}
+	}
+}
+
+func TestNewErrorReport(t *testing.T) {
+	t.Parallel()
+
+	report := cli.NewErrorReport(abcrypt.ErrInvalidMagicNumber)
+
+	expected := cli.ErrorReport{Error: "abcrypt: invalid magic number", Kind: "not-abcrypt", Code: cli.ExitNotAbcrypt}
+	if *report != expected {
+		t.Errorf("expected error report `%+v`, got `%+v`", expected, *report)
+	}
+}
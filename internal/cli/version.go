@@ -0,0 +1,8 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package cli
+
+// Version represents the version number of the module.
+const Version = "0.3.1"
@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/blake2b"
+)
+
+// DerivedKeySize is the number of bytes of the key derived by Argon2.
+//
+// The first 256 bits are for XChaCha20-Poly1305 key, and the last 512 bits are
+// for BLAKE2b-512-MAC key.
+const DerivedKeySize = derivedKeySize
+
+// KeyDeriver is the interface that wraps the DeriveKey method.
+//
+// DeriveKey derives the [DerivedKeySize] bytes key from the passphrase with
+// the given salt, Argon2 type and Argon2 parameters. The Argon2 version is
+// always 0x13. DeriveKey must not retain the passphrase.
+type KeyDeriver interface {
+	DeriveKey(ctx context.Context, passphrase, salt []byte, argon2Type Argon2Type, params Params) ([]byte, error)
+}
+
+// Argon2KeyDeriver is a [KeyDeriver] which runs Argon2 in the current
+// process.
+//
+// This is used when no other [KeyDeriver] is specified.
+type Argon2KeyDeriver struct{}
+
+// DeriveKey derives the key using Argon2 in the current process.
+//
+// DeriveKey returns an [UnsupportedKDFError] for Argon2d, an invalid Argon2
+// type or a degree of parallelism over 255.
+func (Argon2KeyDeriver) DeriveKey(ctx context.Context, passphrase, salt []byte, argon2Type Argon2Type, params Params) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := checkKDF(argon2Type, Argon2Version0x13, params.TimeCost, params.Parallelism); err != nil {
+		return nil, err
+	}
+
+	t := params.TimeCost
+	m := params.MemoryCost
+	p := uint8(params.Parallelism)
+
+	if argon2Type == Argon2i {
+		return argon2.Key(passphrase, salt, t, m, p, derivedKeySize), nil
+	}
+
+	return argon2.IDKey(passphrase, salt, t, m, p, derivedKeySize), nil
+}
+
+// DeriveKeyWithOptions derives the [DerivedKeySize] bytes key from the
+// passphrase with the given salt, Argon2 type and Argon2 parameters, in the
+// same way as the encryptor and the decryptor with the given options.
+//
+// The options except for [WithKeyDeriver], [WithMemoryBudget],
+// [WithMemoryBudgetNoWait], [WithObserver] and [WithKeyfile] are ignored.
+// ctx is used while waiting for a [MemoryBudget].
+func DeriveKeyWithOptions(ctx context.Context, passphrase, salt []byte, argon2Type Argon2Type, params Params, opts ...Option) ([]byte, error) {
+	if len(salt) != saltSize {
+		return nil, ErrInvalidSaltLength
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	h := header{
+		argon2Type:    argon2Type,
+		argon2Version: Argon2Version0x13,
+		memoryCost:    params.MemoryCost,
+		timeCost:      params.TimeCost,
+		parallelism:   params.Parallelism,
+		salt:          [saltSize]byte(salt),
+	}
+
+	dk, err := deriveKey(ctx, o, OperationDerive, &h, passphrase, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Concat(dk.encrypt[:], dk.mac[:]), nil
+}
+
+// checkKDF returns an [UnsupportedKDFError] if the key derivation with the
+// Argon2 type, the Argon2 version, the number of iterations and the degree of
+// parallelism is not supported.
+func checkKDF(argon2Type Argon2Type, argon2Version Argon2Version, timeCost, parallelism uint32) error {
+	switch {
+	case argon2Type == argon2d:
+		return &UnsupportedKDFError{"Argon2d is not supported"}
+	case argon2Type != Argon2i && argon2Type != Argon2id:
+		return &UnsupportedKDFError{fmt.Sprintf("invalid Argon2 type `%v`", uint32(argon2Type))}
+	case argon2Version != Argon2Version0x13:
+		return &UnsupportedKDFError{fmt.Sprintf("Argon2 version `%v` is not supported", argon2Version)}
+	case timeCost < 1:
+		return &UnsupportedKDFError{"`timeCost` less than 1 is not supported"}
+	case parallelism < 1:
+		return &UnsupportedKDFError{"`parallelism` less than 1 is not supported"}
+	case parallelism > math.MaxUint8:
+		return &UnsupportedKDFError{fmt.Sprintf("`parallelism` over %v is not supported", math.MaxUint8)}
+	default:
+		return nil
+	}
+}
+
+func deriveKey(ctx context.Context, o *options, op Operation, h *header, passphrase, pepper []byte) (*derivedKey, error) {
+	if err := checkKDF(h.argon2Type, h.argon2Version, h.timeCost, h.parallelism); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if b := o.budget; b != nil {
+		m := uint64(h.memoryCost)
+
+		if o.budgetWait {
+			if err := b.Acquire(ctx, m); err != nil {
+				return nil, err
+			}
+		} else if err := b.TryAcquire(m); err != nil {
+			return nil, err
+		}
+
+		defer b.Release(m)
+	}
+
+	deriver := o.deriver
+	if deriver == nil {
+		deriver = Argon2KeyDeriver{}
+	}
+
+	if o.keyfile != nil {
+		combined := keyfilePassphrase(passphrase, o.keyfile)
+		defer clear(combined)
+
+		passphrase = combined
+	}
+
+	if pepper != nil {
+		peppered, err := pepperPassphrase(passphrase, pepper)
+		if err != nil {
+			return nil, err
+		}
+		defer clear(peppered)
+
+		passphrase = peppered
+	}
+
+	// The derived key size is 96 bytes. The first 256 bits are for
+	// XChaCha20-Poly1305 key, and the last 512 bits are for
+	// BLAKE2b-512-MAC key.
+	params := Params{h.memoryCost, h.timeCost, h.parallelism}
+	info := KDFInfo{op, h.argon2Type, params}
+
+	o.observer.KDFStart(info)
+	start := time.Now()
+
+	k, err := deriver.DeriveKey(ctx, passphrase, h.salt[:], h.argon2Type, params)
+	if err == nil && len(k) != derivedKeySize {
+		err = ErrInvalidDerivedKeyLength
+	}
+
+	o.observer.KDFEnd(info, time.Since(start), err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newDerivedKey([derivedKeySize]byte(k)), nil
+}
+
+// keyfilePassphrase returns the BLAKE2b-512 hash of the passphrase keyed with
+// the hash of the keyfile, so the passphrase and the keyfile cannot be shifted
+// into each other.
+func keyfilePassphrase(passphrase []byte, keyfile *[blake2b.Size]byte) []byte {
+	h, err := blake2b.New512(keyfile[:])
+	if err != nil {
+		panic(err)
+	}
+
+	h.Write(passphrase)
+
+	return h.Sum(nil)
+}
+
+// unlock derives the key which authenticates the header of the ciphertext, and
+// returns it together with the ID of the pepper used.
+//
+// Without a [PepperSource], the MAC of the header is left to the caller.
+func unlock(ctx context.Context, o *options, h *header, ciphertext, passphrase []byte) (*derivedKey, string, error) {
+	if o.pepper == nil {
+		dk, err := deriveKey(ctx, o, OperationDecrypt, h, passphrase, nil)
+
+		return dk, "", err
+	}
+
+	ids, err := o.pepper.PepperIDs()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(ids) == 0 {
+		return nil, "", ErrNoPepper
+	}
+
+	for _, id := range ids {
+		pepper, err := o.pepper.Pepper(id)
+		if err != nil {
+			return nil, "", err
+		}
+
+		dk, err := deriveKey(ctx, o, OperationDecrypt, h, passphrase, pepper)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if h.verifyMAC(dk.mac[:], ciphertext[84:HeaderSize]) == nil {
+			return dk, id, nil
+		}
+	}
+
+	o.observer.HeaderMACFailure()
+
+	return nil, "", &InvalidHeaderMACError{[64]byte(ciphertext[84:HeaderSize])}
+}
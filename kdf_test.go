@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+type keyDeriverFunc func(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error)
+
+func (f keyDeriverFunc) DeriveKey(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+	return f(ctx, passphrase, salt, argon2Type, params)
+}
+
+func TestDerivedKeySize(t *testing.T) {
+	t.Parallel()
+
+	if size := abcrypt.DerivedKeySize; size != 96 {
+		t.Errorf("expected DerivedKeySize `%v`, got `%v`", 96, size)
+	}
+}
+
+func TestKeyDeriver(t *testing.T) {
+	t.Parallel()
+
+	dataEnc, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile("testdata/data.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var called bool
+
+	deriver := keyDeriverFunc(func(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+		called = true
+
+		if argon2Type != abcrypt.Argon2id {
+			t.Errorf("expected Argon2 type `%v`, got `%v`", abcrypt.Argon2id, argon2Type)
+		}
+
+		if !slices.Equal(salt, dataEnc[28:60]) {
+			t.Error("unexpected salt")
+		}
+
+		return abcrypt.Argon2KeyDeriver{}.DeriveKey(ctx, passphrase, salt, argon2Type, params)
+	})
+
+	plaintext, err := abcrypt.DecryptWithOptions(context.Background(), dataEnc, []byte(passphrase), abcrypt.WithKeyDeriver(deriver))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !called {
+		t.Error("expected the key deriver to be called")
+	}
+
+	if !slices.Equal(plaintext, data) {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func TestKeyDeriverInvalidLength(t *testing.T) {
+	t.Parallel()
+
+	deriver := keyDeriverFunc(func(context.Context, []byte, []byte, abcrypt.Argon2Type, abcrypt.Params) ([]byte, error) {
+		return make([]byte, 32), nil
+	})
+
+	_, err := abcrypt.NewEncryptorWithOptions(context.Background(), []byte(data), []byte(passphrase), abcrypt.WithKeyDeriver(deriver))
+	if !errors.Is(err, abcrypt.ErrInvalidDerivedKeyLength) {
+		t.Error("unexpected error type")
+	}
+}
+
+func TestArgon2KeyDeriverUnsupported(t *testing.T) {
+	t.Parallel()
+
+	salt := make([]byte, 32)
+
+	for _, tc := range []struct {
+		argon2Type abcrypt.Argon2Type
+		params     abcrypt.Params
+	}{
+		{abcrypt.Argon2d, abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}},
+		{abcrypt.Argon2Type(3), abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}},
+		{abcrypt.Argon2id, abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 256}},
+		{abcrypt.Argon2id, abcrypt.Params{MemoryCost: 32, TimeCost: 0, Parallelism: 4}},
+		{abcrypt.Argon2id, abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 0}},
+	} {
+		var unsupportedKDFErr *abcrypt.UnsupportedKDFError
+		if _, err := (abcrypt.Argon2KeyDeriver{}).DeriveKey(context.Background(), []byte(passphrase), salt, tc.argon2Type, tc.params); !errors.As(err, &unsupportedKDFErr) {
+			t.Errorf("expected error type `%T`, got `%T`", unsupportedKDFErr, err)
+		}
+	}
+}
+
+func TestDeriveKeyWithOptions(t *testing.T) {
+	t.Parallel()
+
+	salt := make([]byte, 32)
+	params := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+
+	expected, err := abcrypt.Argon2KeyDeriver{}.DeriveKey(context.Background(), []byte(passphrase), salt, abcrypt.Argon2id, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := abcrypt.DeriveKeyWithOptions(context.Background(), []byte(passphrase), salt, abcrypt.Argon2id, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(k, expected) {
+		t.Error("unexpected mismatch between derived keys")
+	}
+
+	b := abcrypt.NewMemoryBudget(32)
+	if err := b.TryAcquire(1); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = abcrypt.DeriveKeyWithOptions(context.Background(), []byte(passphrase), salt, abcrypt.Argon2id, params, abcrypt.WithMemoryBudgetNoWait(b))
+
+	var memoryBudgetError *abcrypt.MemoryBudgetError
+	if !errors.As(err, &memoryBudgetError) {
+		t.Errorf("expected error type `%T`, got `%T`", memoryBudgetError, err)
+	}
+}
+
+func TestDeriveKeyWithOptionsInvalid(t *testing.T) {
+	t.Parallel()
+
+	params := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+
+	if _, err := abcrypt.DeriveKeyWithOptions(context.Background(), []byte(passphrase), make([]byte, 16), abcrypt.Argon2id, params); !errors.Is(err, abcrypt.ErrInvalidSaltLength) {
+		t.Errorf("expected `%v`, got `%v`", abcrypt.ErrInvalidSaltLength, err)
+	}
+
+	var invalidParamsErr *abcrypt.InvalidParamsError
+	if _, err := abcrypt.DeriveKeyWithOptions(context.Background(), []byte(passphrase), make([]byte, 32), abcrypt.Argon2id, abcrypt.Params{MemoryCost: 32, TimeCost: 0, Parallelism: 4}); !errors.As(err, &invalidParamsErr) {
+		t.Errorf("expected error type `%T`, got `%T`", invalidParamsErr, err)
+	}
+
+	var unsupportedKDFErr *abcrypt.UnsupportedKDFError
+	if _, err := abcrypt.DeriveKeyWithOptions(context.Background(), []byte(passphrase), make([]byte, 32), abcrypt.Argon2Type(3), params); !errors.As(err, &unsupportedKDFErr) {
+		t.Errorf("expected error type `%T`, got `%T`", unsupportedKDFErr, err)
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package keyslot
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidLength represents an error due to the encrypted data was shorter
+// than the header.
+var ErrInvalidLength = errors.New("keyslot: encrypted data is shorter than the header")
+
+// ErrInvalidMagicNumber represents an error due to the magic number (file
+// signature) was invalid.
+var ErrInvalidMagicNumber = errors.New("keyslot: invalid magic number")
+
+// ErrInvalidHeaderMAC represents an error due to the MAC of the header was
+// invalid, which means the key slots were tampered with.
+var ErrInvalidHeaderMAC = errors.New("keyslot: invalid header MAC")
+
+// ErrNoMatchingSlot represents an error due to no key slot matched the
+// passphrase.
+var ErrNoMatchingSlot = errors.New("keyslot: no key slot matches the passphrase")
+
+// ErrNoFreeSlot represents an error due to all key slots were used.
+var ErrNoFreeSlot = errors.New("keyslot: no free key slot")
+
+// ErrLastSlot represents an error due to the last used key slot was about to
+// be removed.
+var ErrLastSlot = errors.New("keyslot: cannot remove the last key slot")
+
+// UnknownVersionError represents an error due to the version was the
+// unrecognized version number.
+type UnknownVersionError struct {
+	// Version represents the obtained version number.
+	Version byte
+}
+
+// Error returns a string representation of an [UnknownVersionError].
+func (e *UnknownVersionError) Error() string {
+	return fmt.Sprintf("keyslot: unknown version number `%v`", e.Version)
+}
+
+// InvalidSlotError represents an error due to a key slot was malformed.
+type InvalidSlotError struct {
+	// Index represents the index of the key slot.
+	Index int
+
+	// Err represents the cause.
+	Err error
+}
+
+// Error returns a string representation of an [InvalidSlotError].
+func (e *InvalidSlotError) Error() string {
+	return fmt.Sprintf("keyslot: invalid key slot `%v`: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error of an [InvalidSlotError].
+func (e *InvalidSlotError) Unwrap() error {
+	return e.Err
+}
+
+// UnusedSlotError represents an error due to the specified key slot was not
+// used.
+type UnusedSlotError struct {
+	// Index represents the specified index.
+	Index int
+}
+
+// Error returns a string representation of an [UnusedSlotError].
+func (e *UnusedSlotError) Error() string {
+	return fmt.Sprintf("keyslot: key slot `%v` is not used", e.Index)
+}
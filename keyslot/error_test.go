@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package keyslot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/keyslot"
+)
+
+func TestUnknownVersionError(t *testing.T) {
+	t.Parallel()
+
+	err := &keyslot.UnknownVersionError{2}
+
+	expected := "keyslot: unknown version number `2`"
+	if err := err.Error(); err != expected {
+		t.Errorf("expected `%v`, got `%v`", expected, err)
+	}
+}
+
+func TestInvalidSlotError(t *testing.T) {
+	t.Parallel()
+
+	err := &keyslot.InvalidSlotError{3, abcrypt.ErrInvalidMagicNumber}
+
+	expected := "keyslot: invalid key slot `3`: abcrypt: invalid magic number"
+	if err := err.Error(); err != expected {
+		t.Errorf("expected `%v`, got `%v`", expected, err)
+	}
+
+	if !errors.Is(err, abcrypt.ErrInvalidMagicNumber) {
+		t.Errorf("expected error `%v`, got `%v`", abcrypt.ErrInvalidMagicNumber, errors.Unwrap(err))
+	}
+}
+
+func TestUnusedSlotError(t *testing.T) {
+	t.Parallel()
+
+	err := &keyslot.UnusedSlotError{5}
+
+	expected := "keyslot: key slot `5` is not used"
+	if err := err.Error(); err != expected {
+		t.Errorf("expected `%v`, got `%v`", expected, err)
+	}
+}
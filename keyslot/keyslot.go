@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package keyslot implements a container format which allows a file to be
+// decrypted with any of several passphrases.
+//
+// A random data key encrypts the payload once, and each of up to [MaxSlots]
+// key slots holds the data key encrypted with a passphrase in the abcrypt
+// encrypted data format. Since the slots are stored in a fixed size header,
+// [AddSlot] and [RemoveSlot] only modify the header and never rewrite the
+// payload. To update a file, it is enough to read the first [HeaderSize]
+// bytes, modify them and write them back.
+//
+// The structure of the container is as follows:
+//
+//	| Offset | Bytes  | Description                              |
+//	| ------ | ------ | ---------------------------------------- |
+//	| 0      | 7      | Magic number ("abcslot")                 |
+//	| 7      | 1      | Version number                           |
+//	| 8      | 24     | XChaCha20-Poly1305 nonce for the payload |
+//	| 32     | 2080   | Key slots (8 slots of 260 bytes)         |
+//	| 2112   | 64     | BLAKE2b-512-MAC of the above             |
+//	| 2176   | n + 16 | Payload                                  |
+//
+// An unused key slot is filled with zeros. A used key slot is the 96 bytes
+// data key encrypted in the abcrypt encrypted data format. The first 32 bytes
+// of the data key are the XChaCha20-Poly1305 key for the payload, and the last
+// 64 bytes are the BLAKE2b-512-MAC key for the header.
+package keyslot
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+
+	"github.com/sorairolake/abcrypt-go"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// MaxSlots is the maximum number of the key slots.
+	MaxSlots = 8
+
+	// SlotSize is the number of bytes of a key slot.
+	SlotSize = abcrypt.HeaderSize + dataKeySize + abcrypt.TagSize
+
+	// HeaderSize is the number of bytes of the header.
+	HeaderSize = slotsOffset + MaxSlots*SlotSize + blake2b.Size
+)
+
+const (
+	version     = 1
+	dataKeySize = 96
+	nonceOffset = 8
+	slotsOffset = nonceOffset + chacha20poly1305.NonceSizeX
+	macOffset   = HeaderSize - blake2b.Size
+)
+
+var magicNumber = [7]byte([]byte("abcslot"))
+
+// Slot represents a used key slot.
+type Slot struct {
+	// Index represents the index of the key slot.
+	Index int `json:"index"`
+
+	// Argon2Type represents the Argon2 type of the key slot.
+	Argon2Type abcrypt.Argon2Type `json:"argon2Type"`
+
+	// Params represents the Argon2 parameters of the key slot.
+	Params abcrypt.Params `json:"params"`
+}
+
+// Encrypt encrypts the plaintext and stores the data key in the first key
+// slot with the given passphrase.
+//
+// opts are passed to [abcrypt.NewEncryptorWithOptions] to encrypt the data
+// key.
+func Encrypt(ctx context.Context, plaintext, passphrase []byte, opts ...abcrypt.Option) ([]byte, error) {
+	var dataKey [dataKeySize]byte
+	if _, err := rand.Read(dataKey[:]); err != nil {
+		panic(err)
+	}
+	defer clear(dataKey[:])
+
+	slot, err := abcrypt.EncryptWithOptions(ctx, dataKey[:], passphrase, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, HeaderSize, HeaderSize+len(plaintext)+abcrypt.TagSize)
+	copy(out, magicNumber[:])
+	out[7] = version
+
+	if _, err := rand.Read(out[nonceOffset:slotsOffset]); err != nil {
+		panic(err)
+	}
+
+	copy(out[slotsOffset:], slot)
+	computeMAC(dataKey[:], out)
+
+	aead := newAEAD(dataKey[:])
+
+	return aead.Seal(out, out[nonceOffset:slotsOffset], plaintext, out[:slotsOffset]), nil
+}
+
+// Decrypt decrypts the ciphertext with the data key stored in any key slot
+// which matches the passphrase.
+//
+// opts are passed to [abcrypt.NewDecryptorWithOptions] to decrypt the data
+// key.
+func Decrypt(ctx context.Context, ciphertext, passphrase []byte, opts ...abcrypt.Option) ([]byte, error) {
+	if len(ciphertext) < HeaderSize+abcrypt.TagSize {
+		return nil, ErrInvalidLength
+	}
+
+	dataKey, err := unlock(ctx, ciphertext, passphrase, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(dataKey)
+
+	aead := newAEAD(dataKey)
+
+	plaintext, err := aead.Open(nil, ciphertext[nonceOffset:slotsOffset], ciphertext[HeaderSize:], ciphertext[:slotsOffset])
+	if err != nil {
+		return nil, &abcrypt.InvalidMACError{Err: err}
+	}
+
+	return plaintext, nil
+}
+
+// ListSlots returns the used key slots of the ciphertext.
+//
+// This does not require a passphrase and does not verify the MAC of the
+// header.
+func ListSlots(ciphertext []byte) ([]Slot, error) {
+	if err := parse(ciphertext); err != nil {
+		return nil, err
+	}
+
+	var slots []Slot
+
+	for i := range MaxSlots {
+		slot := slotAt(ciphertext, i)
+		if isEmpty(slot) {
+			continue
+		}
+
+		h, err := abcrypt.NewHeader(slot)
+		if err != nil {
+			return nil, &InvalidSlotError{i, err}
+		}
+
+		slots = append(slots, Slot{i, h.Argon2Type, h.Params()})
+	}
+
+	return slots, nil
+}
+
+// AddSlot stores the data key in an unused key slot with newPassphrase, and
+// returns the index of the key slot. passphrase must match one of the used key
+// slots.
+//
+// Only the first [HeaderSize] bytes of the ciphertext are read and modified
+// in place. opts are used for both decrypting the data key with passphrase
+// and encrypting it with newPassphrase.
+func AddSlot(ctx context.Context, ciphertext, passphrase, newPassphrase []byte, opts ...abcrypt.Option) (int, error) {
+	dataKey, err := unlock(ctx, ciphertext, passphrase, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer clear(dataKey)
+
+	index := -1
+
+	for i := range MaxSlots {
+		if isEmpty(slotAt(ciphertext, i)) {
+			index = i
+
+			break
+		}
+	}
+
+	if index < 0 {
+		return 0, ErrNoFreeSlot
+	}
+
+	slot, err := abcrypt.EncryptWithOptions(ctx, dataKey, newPassphrase, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	copy(slotAt(ciphertext, index), slot)
+	computeMAC(dataKey, ciphertext)
+
+	return index, nil
+}
+
+// RemoveSlot clears the key slot at index. passphrase must match one of the
+// used key slots, which may be the key slot to remove.
+//
+// The last used key slot cannot be removed. Only the first [HeaderSize] bytes
+// of the ciphertext are read and modified in place.
+func RemoveSlot(ctx context.Context, ciphertext, passphrase []byte, index int, opts ...abcrypt.Option) error {
+	dataKey, err := unlock(ctx, ciphertext, passphrase, opts)
+	if err != nil {
+		return err
+	}
+	defer clear(dataKey)
+
+	if index < 0 || index >= MaxSlots || isEmpty(slotAt(ciphertext, index)) {
+		return &UnusedSlotError{index}
+	}
+
+	used := 0
+
+	for i := range MaxSlots {
+		if !isEmpty(slotAt(ciphertext, i)) {
+			used++
+		}
+	}
+
+	if used == 1 {
+		return ErrLastSlot
+	}
+
+	clear(slotAt(ciphertext, index))
+	computeMAC(dataKey, ciphertext)
+
+	return nil
+}
+
+// unlock decrypts the data key with the first key slot which matches the
+// passphrase, and verifies the MAC of the header.
+func unlock(ctx context.Context, ciphertext, passphrase []byte, opts []abcrypt.Option) ([]byte, error) {
+	if err := parse(ciphertext); err != nil {
+		return nil, err
+	}
+
+	for i := range MaxSlots {
+		slot := slotAt(ciphertext, i)
+		if isEmpty(slot) {
+			continue
+		}
+
+		if _, err := abcrypt.NewHeader(slot); err != nil {
+			return nil, &InvalidSlotError{i, err}
+		}
+
+		dataKey, err := abcrypt.DecryptWithOptions(ctx, slot, passphrase, opts...)
+		if err != nil {
+			var headerMACErr *abcrypt.InvalidHeaderMACError
+			if errors.As(err, &headerMACErr) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if len(dataKey) != dataKeySize {
+			clear(dataKey)
+
+			return nil, &InvalidSlotError{i, abcrypt.ErrInvalidLength}
+		}
+
+		if !verifyMAC(dataKey, ciphertext) {
+			clear(dataKey)
+
+			return nil, ErrInvalidHeaderMAC
+		}
+
+		return dataKey, nil
+	}
+
+	return nil, ErrNoMatchingSlot
+}
+
+func parse(ciphertext []byte) error {
+	if len(ciphertext) < HeaderSize {
+		return ErrInvalidLength
+	}
+
+	if [7]byte(ciphertext[:7]) != magicNumber {
+		return ErrInvalidMagicNumber
+	}
+
+	if v := ciphertext[7]; v != version {
+		return &UnknownVersionError{v}
+	}
+
+	return nil
+}
+
+func slotAt(header []byte, i int) []byte {
+	offset := slotsOffset + i*SlotSize
+
+	return header[offset : offset+SlotSize : offset+SlotSize]
+}
+
+func isEmpty(slot []byte) bool {
+	for _, b := range slot {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newAEAD(dataKey []byte) cipher.AEAD {
+	aead, err := chacha20poly1305.NewX(dataKey[:chacha20poly1305.KeySize])
+	if err != nil {
+		panic(err)
+	}
+
+	return aead
+}
+
+func sum(dataKey, header []byte) []byte {
+	mac, err := blake2b.New512(dataKey[chacha20poly1305.KeySize:])
+	if err != nil {
+		panic(err)
+	}
+
+	mac.Write(header[:macOffset])
+
+	return mac.Sum(nil)
+}
+
+func computeMAC(dataKey, header []byte) {
+	copy(header[macOffset:HeaderSize], sum(dataKey, header))
+}
+
+func verifyMAC(dataKey, header []byte) bool {
+	return hmac.Equal(sum(dataKey, header), header[macOffset:HeaderSize])
+}
@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package keyslot_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/keyslot"
+)
+
+const data = "Hello, world!\n"
+
+var opts = []abcrypt.Option{abcrypt.WithParams(32, 3, 4)}
+
+func encrypt(t *testing.T) []byte {
+	t.Helper()
+
+	ciphertext, err := keyslot.Encrypt(context.Background(), []byte(data), []byte("alice"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ciphertext
+}
+
+func TestHeaderSize(t *testing.T) {
+	t.Parallel()
+
+	if size := keyslot.HeaderSize; size != 2176 {
+		t.Errorf("expected HeaderSize `%v`, got `%v`", 2176, size)
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encrypt(t)
+
+	if n := len(ciphertext); n != keyslot.HeaderSize+len(data)+abcrypt.TagSize {
+		t.Errorf("expected ciphertext length `%v`, got `%v`", keyslot.HeaderSize+len(data)+abcrypt.TagSize, n)
+	}
+
+	plaintext, err := keyslot.Decrypt(context.Background(), ciphertext, []byte("alice"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Errorf("expected plaintext `%v`, got `%v`", data, string(plaintext))
+	}
+
+	if _, err := keyslot.Decrypt(context.Background(), ciphertext, []byte("mallory"), opts...); !errors.Is(err, keyslot.ErrNoMatchingSlot) {
+		t.Errorf("expected error `%v`, got `%v`", keyslot.ErrNoMatchingSlot, err)
+	}
+}
+
+func TestAddSlot(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encrypt(t)
+	payload := bytes.Clone(ciphertext[keyslot.HeaderSize:])
+
+	for i, passphrase := range []string{"bob", "carol"} {
+		index, err := keyslot.AddSlot(context.Background(), ciphertext, []byte("alice"), []byte(passphrase), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if index != i+1 {
+			t.Errorf("expected index `%v`, got `%v`", i+1, index)
+		}
+	}
+
+	if !bytes.Equal(ciphertext[keyslot.HeaderSize:], payload) {
+		t.Error("expected payload to be unchanged")
+	}
+
+	for _, passphrase := range []string{"alice", "bob", "carol"} {
+		plaintext, err := keyslot.Decrypt(context.Background(), ciphertext, []byte(passphrase), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(plaintext) != data {
+			t.Errorf("expected plaintext `%v`, got `%v`", data, string(plaintext))
+		}
+	}
+
+	slots, err := keyslot.ListSlots(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(slots); n != 3 {
+		t.Fatalf("expected number of slots `%v`, got `%v`", 3, n)
+	}
+
+	expected := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+	if slots[2].Index != 2 || slots[2].Argon2Type != abcrypt.Argon2id || slots[2].Params != expected {
+		t.Errorf("unexpected slot `%+v`", slots[2])
+	}
+}
+
+func TestAddSlotHeaderOnly(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encrypt(t)
+	header := bytes.Clone(ciphertext[:keyslot.HeaderSize])
+
+	if _, err := keyslot.AddSlot(context.Background(), header, []byte("alice"), []byte("bob"), opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	copy(ciphertext, header)
+
+	if _, err := keyslot.Decrypt(context.Background(), ciphertext, []byte("bob"), opts...); err != nil {
+		t.Errorf("expected no error, got `%v`", err)
+	}
+}
+
+func TestAddSlotFull(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encrypt(t)
+
+	for range keyslot.MaxSlots - 1 {
+		if _, err := keyslot.AddSlot(context.Background(), ciphertext, []byte("alice"), []byte("bob"), opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := keyslot.AddSlot(context.Background(), ciphertext, []byte("alice"), []byte("bob"), opts...); !errors.Is(err, keyslot.ErrNoFreeSlot) {
+		t.Errorf("expected error `%v`, got `%v`", keyslot.ErrNoFreeSlot, err)
+	}
+}
+
+func TestRemoveSlot(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encrypt(t)
+
+	if _, err := keyslot.AddSlot(context.Background(), ciphertext, []byte("alice"), []byte("bob"), opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := keyslot.RemoveSlot(context.Background(), ciphertext, []byte("bob"), 0, opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := keyslot.Decrypt(context.Background(), ciphertext, []byte("alice"), opts...); !errors.Is(err, keyslot.ErrNoMatchingSlot) {
+		t.Errorf("expected error `%v`, got `%v`", keyslot.ErrNoMatchingSlot, err)
+	}
+
+	err := keyslot.RemoveSlot(context.Background(), ciphertext, []byte("bob"), 0, opts...)
+
+	var unusedSlotErr *keyslot.UnusedSlotError
+	if !errors.As(err, &unusedSlotErr) {
+		t.Errorf("expected error type `%T`, got `%T`", unusedSlotErr, err)
+	}
+
+	if err := keyslot.RemoveSlot(context.Background(), ciphertext, []byte("bob"), 1, opts...); !errors.Is(err, keyslot.ErrLastSlot) {
+		t.Errorf("expected error `%v`, got `%v`", keyslot.ErrLastSlot, err)
+	}
+}
+
+func TestTamperedSlots(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encrypt(t)
+	other := encrypt(t)
+
+	// Copy a key slot from another container, which is valid by itself but
+	// not authenticated by the header MAC of this container.
+	copy(ciphertext[32+keyslot.SlotSize:], other[32:32+keyslot.SlotSize])
+
+	if _, err := keyslot.Decrypt(context.Background(), ciphertext, []byte("alice"), opts...); !errors.Is(err, keyslot.ErrInvalidHeaderMAC) {
+		t.Errorf("expected error `%v`, got `%v`", keyslot.ErrInvalidHeaderMAC, err)
+	}
+}
+
+func TestTamperedPayload(t *testing.T) {
+	t.Parallel()
+
+	ciphertext := encrypt(t)
+	ciphertext[keyslot.HeaderSize] ^= 1
+
+	_, err := keyslot.Decrypt(context.Background(), ciphertext, []byte("alice"), opts...)
+
+	var invalidMACErr *abcrypt.InvalidMACError
+	if !errors.As(err, &invalidMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", invalidMACErr, err)
+	}
+}
+
+func TestInvalidContainer(t *testing.T) {
+	t.Parallel()
+
+	if _, err := keyslot.ListSlots(make([]byte, 10)); !errors.Is(err, keyslot.ErrInvalidLength) {
+		t.Errorf("expected error `%v`, got `%v`", keyslot.ErrInvalidLength, err)
+	}
+
+	if _, err := keyslot.ListSlots(make([]byte, keyslot.HeaderSize)); !errors.Is(err, keyslot.ErrInvalidMagicNumber) {
+		t.Errorf("expected error `%v`, got `%v`", keyslot.ErrInvalidMagicNumber, err)
+	}
+
+	ciphertext := encrypt(t)
+	ciphertext[7] = 2
+
+	_, err := keyslot.ListSlots(ciphertext)
+
+	var unknownVersionErr *keyslot.UnknownVersionError
+	if !errors.As(err, &unknownVersionErr) {
+		t.Errorf("expected error type `%T`, got `%T`", unknownVersionErr, err)
+	}
+}
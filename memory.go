@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBudget represents a weighted semaphore which limits the total amount
+// of memory used by concurrent Argon2 key derivations.
+//
+// The weights are in KiB, the same unit as the memory cost of the Argon2
+// parameters. Waiters are admitted in FIFO order, so a large request is not
+// starved by a stream of small ones.
+type MemoryBudget struct {
+	mu      sync.Mutex
+	limit   uint64
+	inUse   uint64
+	waiters list.List
+	stats   MemoryBudgetStats
+}
+
+// MemoryBudgetStats represents the statistics of a [MemoryBudget].
+type MemoryBudgetStats struct {
+	// Limit represents the total memory size in KiB.
+	Limit uint64
+
+	// InUse represents the memory size in KiB which is currently acquired.
+	InUse uint64
+
+	// Waiting represents the number of callers which are currently waiting.
+	Waiting int
+
+	// Acquired represents the total number of successful acquisitions.
+	Acquired uint64
+
+	// Rejected represents the total number of acquisitions which failed
+	// because the memory could not be admitted.
+	Rejected uint64
+
+	// Canceled represents the total number of acquisitions which were
+	// abandoned because the context was done.
+	Canceled uint64
+
+	// WaitTime represents the total time spent waiting in the queue.
+	WaitTime time.Duration
+}
+
+type waiter struct {
+	n     uint64
+	ready chan struct{}
+}
+
+// NewMemoryBudget creates a new [MemoryBudget] with the given total memory
+// size in KiB.
+func NewMemoryBudget(limit uint64) *MemoryBudget {
+	b := MemoryBudget{limit: limit}
+
+	return &b
+}
+
+// Acquire acquires n KiB from the budget, blocking until the memory is
+// available or ctx is done.
+//
+// On failure, this returns ctx.Err() and leaves the budget unchanged. If n
+// exceeds the total memory size of the budget, this returns a
+// [*MemoryBudgetError] without waiting.
+func (b *MemoryBudget) Acquire(ctx context.Context, n uint64) error {
+	start := time.Now()
+	done := ctx.Done()
+
+	b.mu.Lock()
+
+	select {
+	case <-done:
+		b.stats.Canceled++
+		b.mu.Unlock()
+
+		return ctx.Err()
+	default:
+	}
+
+	if n > b.limit {
+		b.stats.Rejected++
+		err := b.newError(n)
+		b.mu.Unlock()
+
+		return err
+	}
+
+	if b.limit-b.inUse >= n && b.waiters.Len() == 0 {
+		b.inUse += n
+		b.stats.Acquired++
+		b.mu.Unlock()
+
+		return nil
+	}
+
+	ready := make(chan struct{})
+	elem := b.waiters.PushBack(waiter{n, ready})
+	b.mu.Unlock()
+
+	select {
+	case <-done:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.stats.WaitTime += time.Since(start)
+		b.stats.Canceled++
+
+		select {
+		case <-ready:
+			// Acquired after the context was done. Put the memory back
+			// and wake the waiters which it is enough for.
+			b.inUse -= n
+			b.notifyWaiters()
+
+			return ctx.Err()
+		default:
+		}
+
+		isFront := b.waiters.Front() == elem
+		b.waiters.Remove(elem)
+
+		// If we were at the front and there is extra memory, wake the
+		// following waiters.
+		if isFront && b.limit > b.inUse {
+			b.notifyWaiters()
+		}
+
+		return ctx.Err()
+	case <-ready:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.stats.WaitTime += time.Since(start)
+
+		// The context may have been done at the same time.
+		select {
+		case <-done:
+			b.stats.Canceled++
+			b.inUse -= n
+			b.notifyWaiters()
+
+			return ctx.Err()
+		default:
+		}
+
+		b.stats.Acquired++
+
+		return nil
+	}
+}
+
+// TryAcquire acquires n KiB from the budget without blocking.
+//
+// If the memory is not available immediately, this returns a
+// [*MemoryBudgetError] and leaves the budget unchanged.
+func (b *MemoryBudget) TryAcquire(n uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit-b.inUse < n || b.waiters.Len() > 0 {
+		b.stats.Rejected++
+
+		return b.newError(n)
+	}
+
+	b.inUse += n
+	b.stats.Acquired++
+
+	return nil
+}
+
+// Release releases n KiB to the budget.
+//
+// This panics if more memory is released than is held.
+func (b *MemoryBudget) Release(n uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > b.inUse {
+		panic("abcrypt: released more memory than held")
+	}
+
+	b.inUse -= n
+	b.notifyWaiters()
+}
+
+// Limit returns the total memory size in KiB of the budget.
+func (b *MemoryBudget) Limit() uint64 {
+	return b.limit
+}
+
+// Stats returns the current statistics of the budget.
+func (b *MemoryBudget) Stats() MemoryBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.Limit = b.limit
+	stats.InUse = b.inUse
+	stats.Waiting = b.waiters.Len()
+
+	return stats
+}
+
+func (b *MemoryBudget) newError(n uint64) *MemoryBudgetError {
+	err := MemoryBudgetError{n, b.limit - b.inUse, b.limit}
+
+	return &err
+}
+
+func (b *MemoryBudget) notifyWaiters() {
+	for {
+		next := b.waiters.Front()
+		if next == nil {
+			break
+		}
+
+		w := next.Value.(waiter)
+		if b.limit-b.inUse < w.n {
+			// Not enough memory for the next waiter. Stop here rather than
+			// admitting smaller waiters behind it, so that large requests
+			// are not starved.
+			break
+		}
+
+		b.inUse += w.n
+		b.waiters.Remove(next)
+		close(w.ready)
+	}
+}
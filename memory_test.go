@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+func TestMemoryBudgetAcquire(t *testing.T) {
+	t.Parallel()
+
+	b := abcrypt.NewMemoryBudget(64)
+
+	if err := b.Acquire(context.Background(), 48); err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan error)
+
+	go func() {
+		acquired <- b.Acquire(context.Background(), 32)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("unexpected acquisition beyond the limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.Release(48)
+
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+
+	stats := b.Stats()
+	if inUse := stats.InUse; inUse != 32 {
+		t.Errorf("expected memory in use `%v`, got `%v`", 32, inUse)
+	}
+
+	if acquiredCount := stats.Acquired; acquiredCount != 2 {
+		t.Errorf("expected acquisitions `%v`, got `%v`", 2, acquiredCount)
+	}
+
+	b.Release(32)
+}
+
+func TestMemoryBudgetAcquireCanceled(t *testing.T) {
+	t.Parallel()
+
+	b := abcrypt.NewMemoryBudget(64)
+
+	if err := b.Acquire(context.Background(), 64); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := b.Acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected `%v`, got `%v`", context.DeadlineExceeded, err)
+	}
+
+	stats := b.Stats()
+	if waiting := stats.Waiting; waiting != 0 {
+		t.Errorf("expected waiting `%v`, got `%v`", 0, waiting)
+	}
+
+	if canceled := stats.Canceled; canceled != 1 {
+		t.Errorf("expected canceled `%v`, got `%v`", 1, canceled)
+	}
+
+	b.Release(64)
+}
+
+func TestMemoryBudgetAcquireAfterCanceled(t *testing.T) {
+	t.Parallel()
+
+	b := abcrypt.NewMemoryBudget(64)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The memory is available, but the context is already done.
+	if err := b.Acquire(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected `%v`, got `%v`", context.Canceled, err)
+	}
+
+	if inUse := b.Stats().InUse; inUse != 0 {
+		t.Errorf("expected in use `%v`, got `%v`", 0, inUse)
+	}
+
+	// The memory granted to a waiter whose context is done at the same time
+	// is put back.
+	for range 100 {
+		if err := b.Acquire(context.Background(), 64); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+
+		go func() {
+			errs <- b.Acquire(ctx, 64)
+		}()
+
+		for b.Stats().Waiting == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		go cancel()
+		b.Release(64)
+
+		if err := <-errs; err == nil {
+			b.Release(64)
+		} else if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected `%v`, got `%v`", context.Canceled, err)
+		}
+
+		if inUse := b.Stats().InUse; inUse != 0 {
+			t.Fatalf("expected in use `%v`, got `%v`", 0, inUse)
+		}
+	}
+}
+
+func TestMemoryBudgetAcquireOverLimit(t *testing.T) {
+	t.Parallel()
+
+	b := abcrypt.NewMemoryBudget(64)
+
+	err := b.Acquire(context.Background(), 65)
+
+	var memoryBudgetError *abcrypt.MemoryBudgetError
+	if !errors.As(err, &memoryBudgetError) {
+		t.Fatal("unexpected error type")
+	}
+
+	if requested := memoryBudgetError.Requested; requested != 65 {
+		t.Errorf("expected requested memory `%v`, got `%v`", 65, requested)
+	}
+}
+
+func TestMemoryBudgetTryAcquire(t *testing.T) {
+	t.Parallel()
+
+	b := abcrypt.NewMemoryBudget(64)
+
+	if err := b.TryAcquire(40); err != nil {
+		t.Fatal(err)
+	}
+
+	err := b.TryAcquire(40)
+
+	var memoryBudgetError *abcrypt.MemoryBudgetError
+	if !errors.As(err, &memoryBudgetError) {
+		t.Fatal("unexpected error type")
+	}
+
+	if available := memoryBudgetError.Available; available != 24 {
+		t.Errorf("expected available memory `%v`, got `%v`", 24, available)
+	}
+
+	if limit := memoryBudgetError.Limit; limit != 64 {
+		t.Errorf("expected limit `%v`, got `%v`", 64, limit)
+	}
+
+	if rejected := b.Stats().Rejected; rejected != 1 {
+		t.Errorf("expected rejected `%v`, got `%v`", 1, rejected)
+	}
+
+	b.Release(40)
+
+	if inUse := b.Stats().InUse; inUse != 0 {
+		t.Errorf("expected memory in use `%v`, got `%v`", 0, inUse)
+	}
+}
+
+func TestMemoryBudgetReleaseTooMuch(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+
+	abcrypt.NewMemoryBudget(64).Release(1)
+}
+
+func TestMemoryBudgetLimit(t *testing.T) {
+	t.Parallel()
+
+	if limit := abcrypt.NewMemoryBudget(1024).Limit(); limit != 1024 {
+		t.Errorf("expected limit `%v`, got `%v`", 1024, limit)
+	}
+}
+
+func TestEncryptWithMemoryBudget(t *testing.T) {
+	t.Parallel()
+
+	b := abcrypt.NewMemoryBudget(32)
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), abcrypt.WithParams(32, 3, 4), abcrypt.WithMemoryBudget(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.TryAcquire(32); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = abcrypt.NewDecryptorWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithMemoryBudgetNoWait(b))
+
+	var memoryBudgetError *abcrypt.MemoryBudgetError
+	if !errors.As(err, &memoryBudgetError) {
+		t.Fatal("unexpected error type")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = abcrypt.NewDecryptorWithOptions(ctx, ciphertext, []byte(passphrase), abcrypt.WithMemoryBudget(b))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected `%v`, got `%v`", context.Canceled, err)
+	}
+
+	b.Release(32)
+
+	plaintext, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithMemoryBudget(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+
+	if inUse := b.Stats().InUse; inUse != 0 {
+		t.Errorf("expected memory in use `%v`, got `%v`", 0, inUse)
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import "time"
+
+// Operation is a type that represents the operation which an [Observer] is
+// notified about.
+type Operation string
+
+const (
+	// OperationEncrypt indicates encryption.
+	OperationEncrypt Operation = "encrypt"
+
+	// OperationDecrypt indicates decryption.
+	OperationDecrypt Operation = "decrypt"
+
+	// OperationDerive indicates a key derivation by [DeriveKeyWithOptions].
+	OperationDerive Operation = "derive"
+)
+
+// KDFInfo represents the inputs of a key derivation, except for the
+// passphrase and the salt.
+type KDFInfo struct {
+	// Operation represents the operation which runs the key derivation.
+	Operation Operation
+
+	// Argon2Type represents the Argon2 type.
+	Argon2Type Argon2Type
+
+	// Params represents the Argon2 parameters.
+	Params Params
+}
+
+// Observer is the interface that receives events from the encryptor and the
+// decryptor.
+//
+// The methods are called synchronously, so they should return quickly. An
+// Observer used by concurrent operations must be safe for concurrent use.
+// Embed [NopObserver] to implement only some of the methods.
+type Observer interface {
+	// KDFStart is called before the key derivation runs.
+	KDFStart(info KDFInfo)
+
+	// KDFEnd is called after the key derivation finished with the elapsed
+	// time and the error, if any.
+	KDFEnd(info KDFInfo, elapsed time.Duration, err error)
+
+	// HeaderMACFailure is called when the MAC of the header is invalid,
+	// which usually means the passphrase is incorrect.
+	HeaderMACFailure()
+
+	// PayloadMACFailure is called when the MAC of the ciphertext is invalid,
+	// which means the payload is corrupted.
+	PayloadMACFailure()
+
+	// BytesProcessed is called with the number of plaintext bytes after the
+	// payload was encrypted or decrypted successfully.
+	BytesProcessed(op Operation, n int)
+}
+
+// NopObserver is an [Observer] which does nothing.
+type NopObserver struct{}
+
+// KDFStart does nothing.
+func (NopObserver) KDFStart(KDFInfo) {}
+
+// KDFEnd does nothing.
+func (NopObserver) KDFEnd(KDFInfo, time.Duration, error) {}
+
+// HeaderMACFailure does nothing.
+func (NopObserver) HeaderMACFailure() {}
+
+// PayloadMACFailure does nothing.
+func (NopObserver) PayloadMACFailure() {}
+
+// BytesProcessed does nothing.
+func (NopObserver) BytesProcessed(Operation, int) {}
@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package observer
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// DefaultLatencyBuckets is the default upper bounds of the buckets of the key
+// derivation latency histogram.
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Expvar is an [abcrypt.Observer] which publishes counters and a key
+// derivation latency histogram as an [expvar.Map].
+//
+// The map contains the following variables:
+//
+//   - kdfTotal: the number of finished key derivations.
+//   - kdfErrors: the number of failed key derivations.
+//   - kdfInFlight: the number of running key derivations.
+//   - kdfLatency: the latency histogram, with cumulative counts per upper
+//     bound in seconds, the total count and the sum in seconds.
+//   - headerMACFailures: the number of invalid header MACs.
+//   - payloadMACFailures: the number of invalid ciphertext MACs.
+//   - bytesEncrypted: the number of encrypted plaintext bytes.
+//   - bytesDecrypted: the number of decrypted plaintext bytes.
+type Expvar struct {
+	vars               *expvar.Map
+	kdfTotal           expvar.Int
+	kdfErrors          expvar.Int
+	kdfInFlight        expvar.Int
+	headerMACFailures  expvar.Int
+	payloadMACFailures expvar.Int
+	bytesEncrypted     expvar.Int
+	bytesDecrypted     expvar.Int
+	latency            *histogram
+}
+
+// NewExpvar creates a new [Expvar] and publishes it with the given name.
+//
+// This panics if the name is already registered, like [expvar.Publish].
+func NewExpvar(name string) *Expvar {
+	return NewExpvarWithBuckets(name, DefaultLatencyBuckets)
+}
+
+// NewExpvarWithBuckets creates a new [Expvar] with the given upper bounds of
+// the latency histogram buckets, which must be sorted in ascending order.
+func NewExpvarWithBuckets(name string, buckets []time.Duration) *Expvar {
+	o := Expvar{latency: newHistogram(buckets)}
+
+	o.vars = expvar.NewMap(name)
+	o.vars.Set("kdfTotal", &o.kdfTotal)
+	o.vars.Set("kdfErrors", &o.kdfErrors)
+	o.vars.Set("kdfInFlight", &o.kdfInFlight)
+	o.vars.Set("kdfLatency", o.latency)
+	o.vars.Set("headerMACFailures", &o.headerMACFailures)
+	o.vars.Set("payloadMACFailures", &o.payloadMACFailures)
+	o.vars.Set("bytesEncrypted", &o.bytesEncrypted)
+	o.vars.Set("bytesDecrypted", &o.bytesDecrypted)
+
+	return &o
+}
+
+// Map returns the published [expvar.Map].
+func (o *Expvar) Map() *expvar.Map {
+	return o.vars
+}
+
+// KDFStart counts a running key derivation.
+func (o *Expvar) KDFStart(abcrypt.KDFInfo) {
+	o.kdfInFlight.Add(1)
+}
+
+// KDFEnd counts a finished key derivation and records its latency.
+func (o *Expvar) KDFEnd(_ abcrypt.KDFInfo, elapsed time.Duration, err error) {
+	o.kdfInFlight.Add(-1)
+	o.kdfTotal.Add(1)
+
+	if err != nil {
+		o.kdfErrors.Add(1)
+	}
+
+	o.latency.observe(elapsed)
+}
+
+// HeaderMACFailure counts an invalid header MAC.
+func (o *Expvar) HeaderMACFailure() {
+	o.headerMACFailures.Add(1)
+}
+
+// PayloadMACFailure counts an invalid ciphertext MAC.
+func (o *Expvar) PayloadMACFailure() {
+	o.payloadMACFailures.Add(1)
+}
+
+// BytesProcessed counts the processed bytes.
+func (o *Expvar) BytesProcessed(op abcrypt.Operation, n int) {
+	switch op {
+	case abcrypt.OperationEncrypt:
+		o.bytesEncrypted.Add(int64(n))
+	case abcrypt.OperationDecrypt:
+		o.bytesDecrypted.Add(int64(n))
+	}
+}
+
+// histogram is an [expvar.Var] which holds cumulative bucket counts.
+type histogram struct {
+	mu      sync.Mutex
+	buckets []time.Duration
+	counts  []uint64
+	count   uint64
+	sum     time.Duration
+}
+
+func newHistogram(buckets []time.Duration) *histogram {
+	h := histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+
+	return &h
+}
+
+func (h *histogram) observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, b := range h.buckets {
+		if d <= b {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += d
+}
+
+// String returns the histogram as a JSON object.
+func (h *histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b := []byte(`{"buckets":{`)
+
+	for i, bound := range h.buckets {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		b = append(b, '"')
+		b = strconv.AppendFloat(b, bound.Seconds(), 'g', -1, 64)
+		b = append(b, `":`...)
+		b = strconv.AppendUint(b, h.counts[i], 10)
+	}
+
+	b = append(b, `},"count":`...)
+	b = strconv.AppendUint(b, h.count, 10)
+	b = append(b, `,"sum":`...)
+	b = strconv.AppendFloat(b, h.sum.Seconds(), 'g', -1, 64)
+	b = append(b, '}')
+
+	return string(b)
+}
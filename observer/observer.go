@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package observer implements [abcrypt.Observer] adapters for [log/slog] and
+// [expvar].
+//
+// Use [Multi] to report the events to both.
+package observer
+
+import (
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var (
+	_ abcrypt.Observer = (*Slog)(nil)
+	_ abcrypt.Observer = (*Expvar)(nil)
+	_ abcrypt.Observer = Multi(nil)
+)
+
+// Multi is an [abcrypt.Observer] which reports the events to all observers in
+// order.
+type Multi []abcrypt.Observer
+
+// KDFStart calls KDFStart of all observers.
+func (m Multi) KDFStart(info abcrypt.KDFInfo) {
+	for _, o := range m {
+		o.KDFStart(info)
+	}
+}
+
+// KDFEnd calls KDFEnd of all observers.
+func (m Multi) KDFEnd(info abcrypt.KDFInfo, elapsed time.Duration, err error) {
+	for _, o := range m {
+		o.KDFEnd(info, elapsed, err)
+	}
+}
+
+// HeaderMACFailure calls HeaderMACFailure of all observers.
+func (m Multi) HeaderMACFailure() {
+	for _, o := range m {
+		o.HeaderMACFailure()
+	}
+}
+
+// PayloadMACFailure calls PayloadMACFailure of all observers.
+func (m Multi) PayloadMACFailure() {
+	for _, o := range m {
+		o.PayloadMACFailure()
+	}
+}
+
+// BytesProcessed calls BytesProcessed of all observers.
+func (m Multi) BytesProcessed(op abcrypt.Operation, n int) {
+	for _, o := range m {
+		o.BytesProcessed(op, n)
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package observer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/observer"
+)
+
+const (
+	data       = "Hello, world!\n"
+	passphrase = "passphrase"
+)
+
+func roundTrip(t *testing.T, ob abcrypt.Observer) {
+	t.Helper()
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), abcrypt.WithParams(32, 3, 4), abcrypt.WithObserver(ob))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, []byte("password"), abcrypt.WithObserver(ob)); err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	if _, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithObserver(ob)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlog(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	roundTrip(t, observer.NewSlog(logger))
+
+	out := buf.String()
+
+	if n := strings.Count(out, "key derivation finished"); n != 3 {
+		t.Errorf("expected `%v` key derivations, got `%v`", 3, n)
+	}
+
+	if n := strings.Count(out, "invalid header MAC"); n != 1 {
+		t.Errorf("expected `%v` header MAC failures, got `%v`", 1, n)
+	}
+
+	if !strings.Contains(out, "memoryCost=32") {
+		t.Error("expected the memory cost to be logged")
+	}
+}
+
+func TestExpvar(t *testing.T) {
+	t.Parallel()
+
+	ob := observer.NewExpvar("abcrypt-test")
+	roundTrip(t, ob)
+
+	var vars struct {
+		KDFTotal          int64 `json:"kdfTotal"`
+		KDFInFlight       int64 `json:"kdfInFlight"`
+		HeaderMACFailures int64 `json:"headerMACFailures"`
+		BytesEncrypted    int64 `json:"bytesEncrypted"`
+		BytesDecrypted    int64 `json:"bytesDecrypted"`
+		KDFLatency        struct {
+			Buckets map[string]uint64 `json:"buckets"`
+			Count   uint64            `json:"count"`
+		} `json:"kdfLatency"`
+	}
+
+	if err := json.Unmarshal([]byte(ob.Map().String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+
+	if vars.KDFTotal != 3 {
+		t.Errorf("expected kdfTotal `%v`, got `%v`", 3, vars.KDFTotal)
+	}
+
+	if vars.KDFInFlight != 0 {
+		t.Errorf("expected kdfInFlight `%v`, got `%v`", 0, vars.KDFInFlight)
+	}
+
+	if vars.HeaderMACFailures != 1 {
+		t.Errorf("expected headerMACFailures `%v`, got `%v`", 1, vars.HeaderMACFailures)
+	}
+
+	if vars.BytesEncrypted != int64(len(data)) || vars.BytesDecrypted != int64(len(data)) {
+		t.Errorf("unexpected processed bytes `%v` and `%v`", vars.BytesEncrypted, vars.BytesDecrypted)
+	}
+
+	if vars.KDFLatency.Count != 3 {
+		t.Errorf("expected latency count `%v`, got `%v`", 3, vars.KDFLatency.Count)
+	}
+
+	if n := len(vars.KDFLatency.Buckets); n != len(observer.DefaultLatencyBuckets) {
+		t.Errorf("expected `%v` buckets, got `%v`", len(observer.DefaultLatencyBuckets), n)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ob := observer.NewExpvar("abcrypt-test-multi")
+	roundTrip(t, observer.Multi{observer.NewSlog(logger), ob})
+
+	if buf.Len() == 0 {
+		t.Error("expected log output")
+	}
+
+	if !strings.Contains(ob.Map().Get("kdfTotal").String(), "3") {
+		t.Error("expected kdfTotal to be updated")
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package observer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+// Slog is an [abcrypt.Observer] which writes the events to a [slog.Logger].
+//
+// The end of a key derivation and the number of processed bytes are logged at
+// [slog.LevelDebug], and MAC failures are logged at [slog.LevelWarn].
+type Slog struct {
+	logger *slog.Logger
+}
+
+// NewSlog creates a new [Slog] which writes to logger. If logger is nil,
+// [slog.Default] is used.
+func NewSlog(logger *slog.Logger) *Slog {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	o := Slog{logger}
+
+	return &o
+}
+
+// KDFStart logs the start of a key derivation.
+func (o *Slog) KDFStart(info abcrypt.KDFInfo) {
+	o.logger.LogAttrs(context.Background(), slog.LevelDebug, "abcrypt: key derivation started", kdfAttrs(info)...)
+}
+
+// KDFEnd logs the end of a key derivation.
+func (o *Slog) KDFEnd(info abcrypt.KDFInfo, elapsed time.Duration, err error) {
+	attrs := append(kdfAttrs(info), slog.Duration("elapsed", elapsed))
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		o.logger.LogAttrs(context.Background(), slog.LevelWarn, "abcrypt: key derivation failed", attrs...)
+
+		return
+	}
+
+	o.logger.LogAttrs(context.Background(), slog.LevelDebug, "abcrypt: key derivation finished", attrs...)
+}
+
+// HeaderMACFailure logs an invalid header MAC.
+func (o *Slog) HeaderMACFailure() {
+	o.logger.LogAttrs(context.Background(), slog.LevelWarn, "abcrypt: invalid header MAC")
+}
+
+// PayloadMACFailure logs an invalid ciphertext MAC.
+func (o *Slog) PayloadMACFailure() {
+	o.logger.LogAttrs(context.Background(), slog.LevelWarn, "abcrypt: invalid ciphertext MAC")
+}
+
+// BytesProcessed logs the number of processed bytes.
+func (o *Slog) BytesProcessed(op abcrypt.Operation, n int) {
+	o.logger.LogAttrs(context.Background(), slog.LevelDebug, "abcrypt: payload processed", slog.String("operation", string(op)), slog.Int("bytes", n))
+}
+
+func kdfAttrs(info abcrypt.KDFInfo) []slog.Attr {
+	return []slog.Attr{
+		slog.String("operation", string(info.Operation)),
+		slog.String("argon2Type", info.Argon2Type.String()),
+		slog.Uint64("memoryCost", uint64(info.Params.MemoryCost)),
+		slog.Uint64("timeCost", uint64(info.Params.TimeCost)),
+		slog.Uint64("parallelism", uint64(info.Params.Parallelism)),
+	}
+}
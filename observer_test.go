@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+type recordingObserver struct {
+	abcrypt.NopObserver
+
+	mu     sync.Mutex
+	events []string
+	infos  []abcrypt.KDFInfo
+	bytes  int
+}
+
+func (o *recordingObserver) record(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) KDFStart(info abcrypt.KDFInfo) {
+	o.record("kdfStart")
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.infos = append(o.infos, info)
+}
+
+func (o *recordingObserver) KDFEnd(abcrypt.KDFInfo, time.Duration, error) {
+	o.record("kdfEnd")
+}
+
+func (o *recordingObserver) HeaderMACFailure() {
+	o.record("headerMACFailure")
+}
+
+func (o *recordingObserver) PayloadMACFailure() {
+	o.record("payloadMACFailure")
+}
+
+func (o *recordingObserver) BytesProcessed(_ abcrypt.Operation, n int) {
+	o.record("bytesProcessed")
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.bytes += n
+}
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	var ob recordingObserver
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), abcrypt.WithParams(32, 3, 4), abcrypt.WithObserver(&ob))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithObserver(&ob)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"kdfStart", "kdfEnd", "bytesProcessed", "kdfStart", "kdfEnd", "bytesProcessed"}
+	if !slices.Equal(ob.events, expected) {
+		t.Errorf("expected events `%v`, got `%v`", expected, ob.events)
+	}
+
+	info := abcrypt.KDFInfo{Operation: abcrypt.OperationDecrypt, Argon2Type: abcrypt.Argon2id, Params: abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}}
+	if ob.infos[1] != info {
+		t.Errorf("expected KDF info `%v`, got `%v`", info, ob.infos[1])
+	}
+
+	if n := ob.bytes; n != 2*len(data) {
+		t.Errorf("expected processed bytes `%v`, got `%v`", 2*len(data), n)
+	}
+}
+
+func TestObserverMACFailure(t *testing.T) {
+	t.Parallel()
+
+	dataEnc, err := os.ReadFile("testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ob recordingObserver
+
+	if _, err := abcrypt.NewDecryptorWithOptions(context.Background(), dataEnc, []byte("password"), abcrypt.WithObserver(&ob)); err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	dataEnc[len(dataEnc)-1] ^= 1
+
+	cipher, err := abcrypt.NewDecryptorWithOptions(context.Background(), dataEnc, []byte(passphrase), abcrypt.WithObserver(&ob))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cipher.Decrypt(); err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	expected := []string{"kdfStart", "kdfEnd", "headerMACFailure", "kdfStart", "kdfEnd", "payloadMACFailure"}
+	if !slices.Equal(ob.events, expected) {
+		t.Errorf("expected events `%v`, got `%v`", expected, ob.events)
+	}
+}
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"io/fs"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Option represents an option for [NewEncryptorWithOptions] and
+// [NewDecryptorWithOptions].
+type Option func(*options)
+
+type options struct {
+	argon2Type  Argon2Type
+	memoryCost  uint32
+	timeCost    uint32
+	parallelism uint8
+	budget      *MemoryBudget
+	budgetWait  bool
+	deriver     KeyDeriver
+	observer    Observer
+	keyfile     *[blake2b.Size]byte
+	pepper      PepperSource
+	overwrite   bool
+	verify      bool
+	fileMode    *fs.FileMode
+}
+
+func newOptions(opts []Option) *options {
+	o := options{
+		argon2Type:  defaultArgon2Type,
+		memoryCost:  defaultMemoryCost,
+		timeCost:    defaultTimeCost,
+		parallelism: defaultParallelism,
+		observer:    NopObserver{},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &o
+}
+
+// checkEncrypt reports an error if the Argon2 type or the Argon2 parameters
+// used for encryption are invalid.
+func (o *options) checkEncrypt() error {
+	switch o.argon2Type {
+	case argon2d, Argon2i, Argon2id:
+	default:
+		return &InvalidArgon2TypeError{uint32(o.argon2Type)}
+	}
+
+	params := Params{o.memoryCost, o.timeCost, uint32(o.parallelism)}
+
+	return params.Validate()
+}
+
+// WithArgon2Type sets the Argon2 type used for encryption.
+//
+// The decryptor ignores this option and uses the Argon2 type stored in the
+// header.
+func WithArgon2Type(argon2Type Argon2Type) Option {
+	return func(o *options) {
+		o.argon2Type = argon2Type
+	}
+}
+
+// WithParams sets the Argon2 parameters used for encryption.
+//
+// The decryptor ignores this option and uses the Argon2 parameters stored in
+// the header.
+func WithParams(memoryCost, timeCost uint32, parallelism uint8) Option {
+	return func(o *options) {
+		o.memoryCost = memoryCost
+		o.timeCost = timeCost
+		o.parallelism = parallelism
+	}
+}
+
+// WithMemoryBudget makes the key derivation acquire its memory cost from b
+// before running Argon2, waiting until the memory is available or the context
+// is done.
+func WithMemoryBudget(b *MemoryBudget) Option {
+	return func(o *options) {
+		o.budget = b
+		o.budgetWait = true
+	}
+}
+
+// WithMemoryBudgetNoWait is like [WithMemoryBudget], but the key derivation
+// fails immediately with a [*MemoryBudgetError] if the memory is not
+// available.
+func WithMemoryBudgetNoWait(b *MemoryBudget) Option {
+	return func(o *options) {
+		o.budget = b
+		o.budgetWait = false
+	}
+}
+
+// WithKeyDeriver makes the key derivation use d instead of running Argon2 in
+// the current process.
+func WithKeyDeriver(d KeyDeriver) Option {
+	return func(o *options) {
+		o.deriver = d
+	}
+}
+
+// WithObserver makes the encryptor and the decryptor report events to ob.
+func WithObserver(ob Observer) Option {
+	return func(o *options) {
+		if ob == nil {
+			ob = NopObserver{}
+		}
+
+		o.observer = ob
+	}
+}
+
+// WithKeyfile makes the key derivation require the contents of a keyfile in
+// addition to the passphrase.
+//
+// The passphrase is hashed with BLAKE2b-512 keyed with the BLAKE2b-512 hash of
+// keyfile before the key derivation, so the same keyfile must be given to both
+// the encryptor and the decryptor. Only the hash is retained. A [KeyDeriver]
+// which ignores the given passphrase, such as an agent, also ignores the
+// keyfile.
+func WithKeyfile(keyfile []byte) Option {
+	h := blake2b.Sum512(keyfile)
+
+	return func(o *options) {
+		o.keyfile = &h
+	}
+}
+
+// WithPepper makes the key derivation use a pepper provided by src.
+//
+// The encryptor uses the current pepper, and the decryptor tries the peppers
+// in the order returned by [PepperSource.PepperIDs] until one of them
+// authenticates the header. [Encryptor.PepperID] and [Decryptor.PepperID]
+// report which pepper was used.
+//
+// Each pepper tried runs the key derivation once, so decrypting the data
+// encrypted with the Nth pepper takes N times as long as without a pepper, and
+// a wrong passphrase takes as many key derivations as there are peppers. Keep
+// only a few peppers, and retire the old ones once the data encrypted with them
+// has been re-encrypted.
+func WithPepper(src PepperSource) Option {
+	return func(o *options) {
+		o.pepper = src
+	}
+}
+
+// WithOverwrite makes [EncryptFile] and [DecryptFile] replace the output file
+// if it already exists. The file is replaced with a new one, so its mode is
+// not kept; use [WithFileMode] to keep it.
+func WithOverwrite() Option {
+	return func(o *options) {
+		o.overwrite = true
+	}
+}
+
+// WithVerify makes [EncryptFile] and [DecryptFile] read back the output file
+// and check it against the input before renaming it into place.
+func WithVerify() Option {
+	return func(o *options) {
+		o.verify = true
+	}
+}
+
+// WithFileMode sets the permission bits of the output file of [EncryptFile]
+// and [DecryptFile]. As with [os.OpenFile], the umask applies to them.
+func WithFileMode(perm fs.FileMode) Option {
+	perm &= fs.ModePerm
+
+	return func(o *options) {
+		o.fileMode = &perm
+	}
+}
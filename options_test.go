@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestWithKeyfile(t *testing.T) {
+	t.Parallel()
+
+	keyfile := []byte("keyfile")
+	opts := []abcrypt.Option{abcrypt.WithParams(32, 3, 4), abcrypt.WithKeyfile(keyfile)}
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithKeyfile(keyfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Errorf("expected plaintext `%v`, got `%v`", data, string(plaintext))
+	}
+
+	var headerMACErr *abcrypt.InvalidHeaderMACError
+
+	if _, err := abcrypt.Decrypt(ciphertext, []byte(passphrase)); !errors.As(err, &headerMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", headerMACErr, err)
+	}
+
+	if _, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithKeyfile([]byte("other"))); !errors.As(err, &headerMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", headerMACErr, err)
+	}
+}
+
+func TestWithKeyfileDeriverInput(t *testing.T) {
+	t.Parallel()
+
+	keyfile := []byte("keyfile")
+	digest := blake2b.Sum512(keyfile)
+
+	h, err := blake2b.New512(digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Write([]byte(passphrase))
+	expected := h.Sum(nil)
+
+	deriver := keyDeriverFunc(func(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+		if !slices.Equal(passphrase, expected) {
+			t.Errorf("expected passphrase `%v`, got `%v`", expected, passphrase)
+		}
+
+		return abcrypt.Argon2KeyDeriver{}.DeriveKey(ctx, passphrase, salt, argon2Type, params)
+	})
+
+	opts := []abcrypt.Option{abcrypt.WithParams(32, 3, 4), abcrypt.WithKeyfile(keyfile), abcrypt.WithKeyDeriver(deriver)}
+	if _, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), opts...); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,7 +4,20 @@
 
 package abcrypt
 
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
 // Params represents the Argon2 parameters used for the encrypted data.
+//
+// Params implements [encoding.TextMarshaler], [encoding.TextUnmarshaler] and
+// [flag.Value] using the format of the PHC string format, such as
+// "m=19456,t=2,p=1", so that it can be used in configuration files and
+// command-line flags. The JSON encoding is an object.
 type Params struct {
 	// MemoryCost represents memory size in KiB.
 	MemoryCost uint32 `json:"memoryCost"`
@@ -27,3 +40,164 @@ func NewParams(ciphertext []byte) (*Params, error) {
 
 	return &params, nil
 }
+
+// maxParallelism is the maximum degree of parallelism allowed by RFC 9106.
+const maxParallelism = 1<<24 - 1
+
+// Validate reports whether the Argon2 parameters are within the bounds of
+// [RFC 9106].
+//
+// In addition, the degree of parallelism must not be greater than 255, as
+// this package does not support it.
+//
+// [RFC 9106]: https://www.rfc-editor.org/rfc/rfc9106.html
+func (p Params) Validate() error {
+	switch {
+	case p.Parallelism < 1 || p.Parallelism > maxParallelism:
+		reason := fmt.Sprintf("must be between 1 and %v", maxParallelism)
+
+		return &InvalidParamsError{"parallelism", p.Parallelism, reason}
+	case p.Parallelism > math.MaxUint8:
+		reason := fmt.Sprintf("over %v is not supported", math.MaxUint8)
+
+		return &InvalidParamsError{"parallelism", p.Parallelism, reason}
+	case p.TimeCost < 1:
+		return &InvalidParamsError{"timeCost", p.TimeCost, "must be at least 1"}
+	case uint64(p.MemoryCost) < 8*uint64(p.Parallelism):
+		return &InvalidParamsError{"memoryCost", p.MemoryCost, "must be at least 8 times the degree of parallelism"}
+	}
+
+	return nil
+}
+
+// MemoryBytes returns the memory size in bytes.
+func (p Params) MemoryBytes() uint64 {
+	return uint64(p.MemoryCost) * 1024
+}
+
+// String returns the Argon2 parameters in the format of the PHC string format,
+// such as "m=19456,t=2,p=1".
+func (p Params) String() string {
+	return fmt.Sprintf("m=%v,t=%v,p=%v", p.MemoryCost, p.TimeCost, p.Parallelism)
+}
+
+// ParseParams parses the Argon2 parameters in the format of the PHC string
+// format, such as "m=19456,t=2,p=1".
+//
+// All of m, t and p must be present exactly once, in any order. The parsed
+// parameters are checked with [Params.Validate].
+func ParseParams(s string) (*Params, error) {
+	var (
+		params Params
+		seen   [3]bool
+	)
+
+	fields := strings.Split(s, ",")
+	if len(fields) != len(seen) {
+		return nil, &ParseParamsError{s}
+	}
+
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, &ParseParamsError{s}
+		}
+
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, &ParseParamsError{s}
+		}
+
+		var i int
+
+		switch key {
+		case "m":
+			i = 0
+			params.MemoryCost = uint32(n)
+		case "t":
+			i = 1
+			params.TimeCost = uint32(n)
+		case "p":
+			i = 2
+			params.Parallelism = uint32(n)
+		default:
+			return nil, &ParseParamsError{s}
+		}
+
+		if seen[i] {
+			return nil, &ParseParamsError{s}
+		}
+
+		seen[i] = true
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &params, nil
+}
+
+// MarshalText returns the Argon2 parameters in the format of [Params.String].
+//
+// This implements [encoding.TextMarshaler].
+func (p Params) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText parses the Argon2 parameters with [ParseParams].
+//
+// This implements [encoding.TextUnmarshaler].
+func (p *Params) UnmarshalText(text []byte) error {
+	params, err := ParseParams(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = *params
+
+	return nil
+}
+
+// jsonParams has the same fields as [Params] without its methods, so that the
+// JSON encoding stays an object rather than the text encoding.
+type jsonParams Params
+
+// MarshalJSON returns the Argon2 parameters as a JSON object.
+//
+// This implements [json.Marshaler].
+func (p Params) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonParams(p))
+}
+
+// UnmarshalJSON parses the Argon2 parameters from either a JSON object or a
+// JSON string in the format of [Params.String]. In both cases, the parsed
+// parameters are checked with [Params.Validate].
+//
+// This implements [json.Unmarshaler].
+func (p *Params) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return p.UnmarshalText([]byte(s))
+	}
+
+	var params jsonParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	if err := Params(params).Validate(); err != nil {
+		return err
+	}
+
+	*p = Params(params)
+
+	return nil
+}
+
+// Set parses the Argon2 parameters with [ParseParams].
+//
+// This implements [flag.Value] together with [Params.String].
+func (p *Params) Set(s string) error {
+	return p.UnmarshalText([]byte(s))
+}
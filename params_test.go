@@ -6,6 +6,9 @@ package abcrypt_test
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
+	"math"
 	"os"
 	"testing"
 
@@ -178,3 +181,174 @@ func TestParamsMarshalJSON(t *testing.T) {
 		t.Errorf("expected JSON `%v`, got `%s`", expected, json)
 	}
 }
+
+func TestParamsUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{`{"memoryCost":32,"timeCost":3,"parallelism":4}`, `"m=32,t=3,p=4"`} {
+		var params abcrypt.Params
+		if err := json.Unmarshal([]byte(input), &params); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+		if params != expected {
+			t.Errorf("expected `%v`, got `%v`", expected, params)
+		}
+	}
+}
+
+func TestParamsUnmarshalJSONInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		`{"memoryCost":32,"timeCost":0,"parallelism":4}`,
+		`{"memoryCost":32,"timeCost":3,"parallelism":0}`,
+		`{"memoryCost":32,"timeCost":3,"parallelism":256}`,
+		`"m=32,t=0,p=4"`,
+	} {
+		params := abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1}
+
+		var invalidParamsError *abcrypt.InvalidParamsError
+		if err := json.Unmarshal([]byte(input), &params); !errors.As(err, &invalidParamsError) {
+			t.Errorf("expected error type `%T` for `%v`, got `%T`", invalidParamsError, input, err)
+		}
+
+		if expected := (abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1}); params != expected {
+			t.Errorf("expected `%v` to be kept, got `%v`", expected, params)
+		}
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := (abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1}).Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if err := (abcrypt.Params{MemoryCost: 2040, TimeCost: 1, Parallelism: 255}).Validate(); err != nil {
+		t.Error(err)
+	}
+
+	tests := []struct {
+		params abcrypt.Params
+		param  string
+	}{
+		{abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 0}, "parallelism"},
+		{abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1 << 24}, "parallelism"},
+		{abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 256}, "parallelism"},
+		{abcrypt.Params{MemoryCost: 19456, TimeCost: 0, Parallelism: 1}, "timeCost"},
+		{abcrypt.Params{MemoryCost: 31, TimeCost: 3, Parallelism: 4}, "memoryCost"},
+	}
+
+	for _, test := range tests {
+		err := test.params.Validate()
+
+		var invalidParamsError *abcrypt.InvalidParamsError
+		if !errors.As(err, &invalidParamsError) {
+			t.Fatalf("unexpected error type for `%v`", test.params)
+		}
+
+		if param := invalidParamsError.Param; param != test.param {
+			t.Errorf("expected invalid parameter `%v`, got `%v`", test.param, param)
+		}
+	}
+}
+
+func TestParamsMemoryBytes(t *testing.T) {
+	t.Parallel()
+
+	params := abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1}
+	if b := params.MemoryBytes(); b != 19922944 {
+		t.Errorf("expected memory size `%v`, got `%v`", 19922944, b)
+	}
+
+	params.MemoryCost = math.MaxUint32
+	if b := params.MemoryBytes(); b != math.MaxUint32*1024 {
+		t.Errorf("expected memory size `%v`, got `%v`", uint64(math.MaxUint32)*1024, b)
+	}
+}
+
+func TestParamsString(t *testing.T) {
+	t.Parallel()
+
+	params := abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1}
+	if s := params.String(); s != "m=19456,t=2,p=1" {
+		t.Errorf("expected `%v`, got `%v`", "m=19456,t=2,p=1", s)
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"m=19456,t=2,p=1", "p=1,m=19456,t=2"} {
+		params, err := abcrypt.ParseParams(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1}
+		if *params != expected {
+			t.Errorf("expected `%v`, got `%v`", expected, *params)
+		}
+	}
+
+	for _, input := range []string{"", "m=19456,t=2", "m=19456,t=2,p=1,p=1", "m=19456,t=2,m=1", "m=19456,t=2,x=1", "m=19456,t=-2,p=1", "m=19456;t=2;p=1"} {
+		_, err := abcrypt.ParseParams(input)
+
+		var parseParamsError *abcrypt.ParseParamsError
+		if !errors.As(err, &parseParamsError) {
+			t.Fatalf("unexpected error type for `%v`", input)
+		}
+	}
+
+	_, err := abcrypt.ParseParams("m=8,t=2,p=2")
+
+	var invalidParamsError *abcrypt.InvalidParamsError
+	if !errors.As(err, &invalidParamsError) {
+		t.Fatal("unexpected error type")
+	}
+}
+
+func TestParamsText(t *testing.T) {
+	t.Parallel()
+
+	params := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+
+	text, err := params.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(text) != "m=32,t=3,p=4" {
+		t.Errorf("expected `%v`, got `%s`", "m=32,t=3,p=4", text)
+	}
+
+	var decoded abcrypt.Params
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded != params {
+		t.Errorf("expected `%v`, got `%v`", params, decoded)
+	}
+}
+
+func TestParamsFlag(t *testing.T) {
+	t.Parallel()
+
+	params := abcrypt.Params{MemoryCost: 19456, TimeCost: 2, Parallelism: 1}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&params, "params", "Set the Argon2 parameters")
+
+	if err := fs.Parse([]string{"-params", "m=32,t=3,p=4"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+	if params != expected {
+		t.Errorf("expected `%v`, got `%v`", expected, params)
+	}
+}
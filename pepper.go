@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// MaxPepperSize is the maximum number of bytes of a pepper.
+const MaxPepperSize = blake2b.Size
+
+// PepperSource is the interface that provides the peppers.
+//
+// A pepper is a secret which is held separately from the encrypted data. The
+// passphrase is hashed with BLAKE2b-512 keyed with the pepper before the key
+// derivation, so the encrypted data cannot be decrypted without the pepper.
+//
+// PepperIDs returns the IDs of the available peppers. The first ID is the
+// current pepper, which is used for encryption. Since the ID is not stored in
+// the encrypted data, the decryptor tries the peppers in this order, and each
+// pepper tried costs a key derivation. Pepper
+// returns the pepper with the given ID, which must be 1 to [MaxPepperSize]
+// bytes.
+type PepperSource interface {
+	PepperIDs() ([]string, error)
+	Pepper(id string) ([]byte, error)
+}
+
+// MemoryPepperSource is a [PepperSource] which holds the peppers in memory.
+type MemoryPepperSource struct {
+	ids     []string
+	peppers map[string][]byte
+}
+
+// NewMemoryPepperSource creates a new [MemoryPepperSource] which uses the
+// pepper with currentID for encryption. peppers maps the IDs to the peppers,
+// and must contain currentID, otherwise an [UnknownPepperError] is returned.
+//
+// The other peppers are tried by the decryptor in lexical order of the IDs.
+func NewMemoryPepperSource(currentID string, peppers map[string][]byte) (*MemoryPepperSource, error) {
+	if _, ok := peppers[currentID]; !ok {
+		return nil, &UnknownPepperError{currentID}
+	}
+
+	s := MemoryPepperSource{ids: []string{currentID}, peppers: make(map[string][]byte, len(peppers))}
+
+	for id, pepper := range peppers {
+		s.peppers[id] = bytes.Clone(pepper)
+	}
+
+	var others []string
+
+	for id := range peppers {
+		if id != currentID {
+			others = append(others, id)
+		}
+	}
+
+	slices.Sort(others)
+	s.ids = append(s.ids, others...)
+
+	return &s, nil
+}
+
+// PepperIDs returns the IDs of the peppers, the current one first.
+func (s *MemoryPepperSource) PepperIDs() ([]string, error) {
+	return slices.Clone(s.ids), nil
+}
+
+// Pepper returns the pepper with the given ID.
+func (s *MemoryPepperSource) Pepper(id string) ([]byte, error) {
+	pepper, ok := s.peppers[id]
+	if !ok {
+		return nil, &UnknownPepperError{id}
+	}
+
+	return pepper, nil
+}
+
+// FilePepperSource is a [PepperSource] which reads the peppers from the files
+// in a directory.
+//
+// The name of each file is the ID of the pepper, and the contents are the
+// pepper. The files are read each time the peppers are used, so a new pepper
+// can be added without restarting the program.
+type FilePepperSource struct {
+	// Dir represents the directory which contains the pepper files.
+	Dir string
+
+	// CurrentID represents the ID of the pepper used for encryption. The
+	// other peppers are tried by the decryptor in lexical order of the IDs.
+	CurrentID string
+}
+
+// PepperIDs returns the names of the regular files in the directory, the
+// current ID first.
+func (s *FilePepperSource) PepperIDs() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{s.CurrentID}
+
+	for _, e := range entries {
+		if e.Type().IsRegular() && e.Name() != s.CurrentID && !strings.HasPrefix(e.Name(), ".") {
+			ids = append(ids, e.Name())
+		}
+	}
+
+	return ids, nil
+}
+
+// Pepper reads the pepper with the given ID.
+func (s *FilePepperSource) Pepper(id string) ([]byte, error) {
+	if id == "" || id != filepath.Base(id) || !filepath.IsLocal(id) {
+		return nil, &UnknownPepperError{id}
+	}
+
+	return os.ReadFile(filepath.Join(s.Dir, id))
+}
+
+// currentPepper returns the ID and the pepper used for encryption.
+func currentPepper(s PepperSource) (string, []byte, error) {
+	ids, err := s.PepperIDs()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(ids) == 0 {
+		return "", nil, ErrNoPepper
+	}
+
+	pepper, err := s.Pepper(ids[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ids[0], pepper, nil
+}
+
+// pepperPassphrase returns the BLAKE2b-512 hash of the passphrase keyed with
+// the pepper.
+func pepperPassphrase(passphrase, pepper []byte) ([]byte, error) {
+	if len(pepper) == 0 || len(pepper) > MaxPepperSize {
+		return nil, ErrInvalidPepperLength
+	}
+
+	h, err := blake2b.New512(pepper)
+	if err != nil {
+		panic(err)
+	}
+
+	h.Write(passphrase)
+
+	return h.Sum(nil), nil
+}
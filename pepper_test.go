@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+func encryptWithPepper(t *testing.T, src abcrypt.PepperSource) []byte {
+	t.Helper()
+
+	opts := []abcrypt.Option{abcrypt.WithParams(32, 3, 4), abcrypt.WithPepper(src)}
+
+	cipher, err := abcrypt.NewEncryptorWithOptions(context.Background(), []byte(data), []byte(passphrase), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cipher.Encrypt()
+}
+
+func newMemoryPepperSource(t *testing.T, currentID string, peppers map[string][]byte) *abcrypt.MemoryPepperSource {
+	t.Helper()
+
+	src, err := abcrypt.NewMemoryPepperSource(currentID, peppers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return src
+}
+
+func TestWithPepper(t *testing.T) {
+	t.Parallel()
+
+	src := newMemoryPepperSource(t, "v1", map[string][]byte{"v1": []byte("pepper")})
+	ciphertext := encryptWithPepper(t, src)
+
+	cipher, err := abcrypt.NewDecryptorWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithPepper(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := cipher.PepperID(); id != "v1" {
+		t.Errorf("expected pepper ID `%v`, got `%v`", "v1", id)
+	}
+
+	plaintext, err := cipher.Decrypt()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plaintext) != data {
+		t.Errorf("expected plaintext `%v`, got `%v`", data, string(plaintext))
+	}
+
+	var headerMACErr *abcrypt.InvalidHeaderMACError
+	if _, err := abcrypt.Decrypt(ciphertext, []byte(passphrase)); !errors.As(err, &headerMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", headerMACErr, err)
+	}
+
+	other := newMemoryPepperSource(t, "v1", map[string][]byte{"v1": []byte("other")})
+	if _, err := abcrypt.DecryptWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithPepper(other)); !errors.As(err, &headerMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", headerMACErr, err)
+	}
+}
+
+func TestWithPepperRotation(t *testing.T) {
+	t.Parallel()
+
+	peppers := map[string][]byte{"v1": []byte("old pepper")}
+	ciphertext := encryptWithPepper(t, newMemoryPepperSource(t, "v1", peppers))
+
+	peppers["v2"] = []byte("new pepper")
+	src := newMemoryPepperSource(t, "v2", peppers)
+
+	cipher, err := abcrypt.NewEncryptorWithOptions(context.Background(), []byte(data), []byte(passphrase), abcrypt.WithParams(32, 3, 4), abcrypt.WithPepper(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := cipher.PepperID(); id != "v2" {
+		t.Errorf("expected pepper ID `%v`, got `%v`", "v2", id)
+	}
+
+	d, err := abcrypt.NewDecryptorWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithPepper(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := d.PepperID(); id != "v1" {
+		t.Errorf("expected pepper ID `%v`, got `%v`", "v1", id)
+	}
+}
+
+func TestWithPepperInvalidLength(t *testing.T) {
+	t.Parallel()
+
+	for _, pepper := range [][]byte{{}, make([]byte, abcrypt.MaxPepperSize+1)} {
+		src := newMemoryPepperSource(t, "v1", map[string][]byte{"v1": pepper})
+
+		_, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), abcrypt.WithParams(32, 3, 4), abcrypt.WithPepper(src))
+		if !errors.Is(err, abcrypt.ErrInvalidPepperLength) {
+			t.Errorf("expected error `%v`, got `%v`", abcrypt.ErrInvalidPepperLength, err)
+		}
+	}
+}
+
+func TestMemoryPepperSource(t *testing.T) {
+	t.Parallel()
+
+	src := newMemoryPepperSource(t, "b", map[string][]byte{"c": {3}, "a": {1}, "b": {2}})
+
+	ids, err := src.PepperIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"b", "a", "c"}; !slices.Equal(ids, expected) {
+		t.Errorf("expected IDs `%v`, got `%v`", expected, ids)
+	}
+
+	_, err = src.Pepper("d")
+
+	var unknownPepperErr *abcrypt.UnknownPepperError
+	if !errors.As(err, &unknownPepperErr) {
+		t.Errorf("expected error type `%T`, got `%T`", unknownPepperErr, err)
+	}
+
+	if _, err := abcrypt.NewMemoryPepperSource("d", map[string][]byte{"a": {1}}); !errors.As(err, &unknownPepperErr) {
+		t.Errorf("expected error type `%T`, got `%T`", unknownPepperErr, err)
+	}
+}
+
+func TestFilePepperSource(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for id, pepper := range map[string]string{"2025": "old pepper", "2026": "new pepper"} {
+		if err := os.WriteFile(filepath.Join(dir, id), []byte(pepper), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src := &abcrypt.FilePepperSource{Dir: dir, CurrentID: "2026"}
+
+	ids, err := src.PepperIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"2026", "2025"}; !slices.Equal(ids, expected) {
+		t.Errorf("expected IDs `%v`, got `%v`", expected, ids)
+	}
+
+	ciphertext := encryptWithPepper(t, &abcrypt.FilePepperSource{Dir: dir, CurrentID: "2025"})
+
+	d, err := abcrypt.NewDecryptorWithOptions(context.Background(), ciphertext, []byte(passphrase), abcrypt.WithPepper(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := d.PepperID(); id != "2025" {
+		t.Errorf("expected pepper ID `%v`, got `%v`", "2025", id)
+	}
+
+	_, err = src.Pepper(strings.Join([]string{"..", "2026"}, string(filepath.Separator)))
+
+	var unknownPepperErr *abcrypt.UnknownPepperError
+	if !errors.As(err, &unknownPepperErr) {
+		t.Errorf("expected error type `%T`, got `%T`", unknownPepperErr, err)
+	}
+}
@@ -0,0 +1,9 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build linux && !race
+
+package sandbox_test
+
+const raceEnabled = false
@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build linux && race
+
+package sandbox_test
+
+// The race detector reserves a large address space in the child process, so
+// RLIMIT_AS cannot be applied.
+const raceEnabled = true
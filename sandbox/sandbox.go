@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package sandbox implements a key deriver which runs Argon2 in a child
+// process with resource limits.
+//
+// The child process is the current executable (or a helper program) which
+// calls [Main] at the start of its main function. A hostile header with a huge
+// memory cost can then only exhaust the memory of the child process, which is
+// killed by the kernel when it exceeds RLIMIT_AS or RLIMIT_CPU.
+//
+// The passphrase, salt and Argon2 parameters are sent to the child process
+// over a pipe, never through the command line or the environment.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+const (
+	envChild       = "ABCRYPT_SANDBOX_CHILD"
+	envMemoryLimit = "ABCRYPT_SANDBOX_RLIMIT_AS"
+	envCPULimit    = "ABCRYPT_SANDBOX_RLIMIT_CPU"
+)
+
+// DefaultMaxMemory is the number of bytes of the Argon2 memory allowed when
+// [Deriver.MemoryLimit] is zero. A larger memory cost makes the key derivation
+// fail.
+const DefaultMaxMemory = 1 << 30
+
+// DefaultMemoryHeadroom is the number of bytes of the address space allowed in
+// addition to [DefaultMaxMemory] when [Deriver.MemoryLimit] is zero.
+const DefaultMemoryHeadroom = 64 << 20
+
+// ErrUnsupported represents an error due to the resource limits are not
+// supported on this platform.
+var ErrUnsupported = errors.New("sandbox: resource limits are not supported on this platform")
+
+// Deriver is an [abcrypt.KeyDeriver] which runs Argon2 in a child process.
+type Deriver struct {
+	// Path represents the path of the program to execute. If empty, the
+	// current executable is used.
+	Path string
+
+	// Args represents the arguments passed to the program, not including
+	// the program name.
+	Args []string
+
+	// MemoryLimit represents the maximum size in bytes of the address space
+	// of the child process. If zero, the address space at the start of the
+	// child process plus [DefaultMaxMemory] and [DefaultMemoryHeadroom] is
+	// used, regardless of the memory cost.
+	MemoryLimit uint64
+
+	// CPULimit represents the maximum CPU time of the child process. If
+	// zero, the CPU time is not limited.
+	CPULimit time.Duration
+}
+
+// Error represents an error due to the child process failed.
+type Error struct {
+	// Err represents a wrapped error.
+	Err error
+
+	// Stderr represents the standard error output of the child process.
+	Stderr string
+}
+
+// Error returns a string representation of an [Error].
+func (e *Error) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("sandbox: key derivation failed: %v", e.Err)
+	}
+
+	return fmt.Sprintf("sandbox: key derivation failed: %v: %v", e.Err, e.Stderr)
+}
+
+// Unwrap returns the underlying error of an [Error].
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DeriveKey derives the key in a child process.
+//
+// The child process is killed when ctx is done.
+func (d *Deriver) DeriveKey(ctx context.Context, passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) ([]byte, error) {
+	if !supported {
+		return nil, ErrUnsupported
+	}
+
+	path := d.Path
+	if path == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+
+		path = exe
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path, d.Args...)
+	cmd.Env = append(os.Environ(), envChild+"=1")
+	cmd.Stdin = bytes.NewReader(encodeRequest(passphrase, salt, argon2Type, params))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if d.MemoryLimit != 0 {
+		cmd.Env = append(cmd.Env, envMemoryLimit+"="+strconv.FormatUint(d.MemoryLimit, 10))
+	}
+
+	if d.CPULimit > 0 {
+		// RLIMIT_CPU is in seconds, so round up.
+		secs := uint64((d.CPULimit + time.Second - 1) / time.Second)
+		cmd.Env = append(cmd.Env, envCPULimit+"="+strconv.FormatUint(secs, 10))
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		return nil, &Error{err, string(bytes.TrimSpace(stderr.Bytes()))}
+	}
+
+	if stdout.Len() != abcrypt.DerivedKeySize {
+		return nil, abcrypt.ErrInvalidDerivedKeyLength
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Main runs the child side of [Deriver] if the current process was started
+// by it, and never returns in that case. Otherwise, this returns immediately.
+//
+// Programs using [Deriver] without [Deriver.Path] must call this at the start
+// of their main function, before doing anything else.
+func Main() {
+	if os.Getenv(envChild) == "" {
+		return
+	}
+
+	if err := runChild(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func runChild(r io.Reader, w io.Writer) error {
+	var memoryLimit, cpuLimit uint64
+
+	if s := os.Getenv(envMemoryLimit); s != "" {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		memoryLimit = v
+	}
+
+	if s := os.Getenv(envCPULimit); s != "" {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		cpuLimit = v
+	}
+
+	passphrase, salt, argon2Type, params, err := decodeRequest(r)
+	if err != nil {
+		return err
+	}
+
+	// The limits are applied before allocating the Argon2 memory, so an
+	// excessive memory cost makes the allocation fail. The default limit does
+	// not depend on the memory cost, which comes from an untrusted header.
+	if memoryLimit == 0 {
+		memoryLimit = addressSpace() + DefaultMaxMemory + DefaultMemoryHeadroom
+	}
+
+	if err := setLimits(memoryLimit, cpuLimit); err != nil {
+		return err
+	}
+
+	k, err := abcrypt.Argon2KeyDeriver{}.DeriveKey(context.Background(), passphrase, salt, argon2Type, params)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(k)
+
+	return err
+}
+
+// The request is encoded as the Argon2 type, the memory cost, the time cost
+// and the degree of parallelism as 32-bit little-endian integers, followed by
+// the length-prefixed salt and the length-prefixed passphrase.
+func encodeRequest(passphrase, salt []byte, argon2Type abcrypt.Argon2Type, params abcrypt.Params) []byte {
+	b := make([]byte, 0, 24+len(salt)+len(passphrase))
+	b = binary.LittleEndian.AppendUint32(b, uint32(argon2Type))
+	b = binary.LittleEndian.AppendUint32(b, params.MemoryCost)
+	b = binary.LittleEndian.AppendUint32(b, params.TimeCost)
+	b = binary.LittleEndian.AppendUint32(b, params.Parallelism)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(salt)))
+	b = append(b, salt...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(passphrase)))
+	b = append(b, passphrase...)
+
+	return b
+}
+
+func decodeRequest(r io.Reader) ([]byte, []byte, abcrypt.Argon2Type, abcrypt.Params, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, nil, 0, abcrypt.Params{}, err
+	}
+
+	argon2Type := abcrypt.Argon2Type(binary.LittleEndian.Uint32(fixed[0:4]))
+	params := abcrypt.Params{
+		MemoryCost:  binary.LittleEndian.Uint32(fixed[4:8]),
+		TimeCost:    binary.LittleEndian.Uint32(fixed[8:12]),
+		Parallelism: binary.LittleEndian.Uint32(fixed[12:16]),
+	}
+
+	salt, err := readBytes(r)
+	if err != nil {
+		return nil, nil, 0, abcrypt.Params{}, err
+	}
+
+	passphrase, err := readBytes(r)
+	if err != nil {
+		return nil, nil, 0, abcrypt.Params{}, err
+	}
+
+	return passphrase, salt, argon2Type, params, nil
+}
+
+// maxFieldSize is the maximum length of the salt and the passphrase accepted
+// by the child process.
+const maxFieldSize = 1 << 20
+
+func readBytes(r io.Reader) ([]byte, error) {
+	var l [4]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, err
+	}
+
+	n := binary.LittleEndian.Uint32(l[:])
+	if n > maxFieldSize {
+		return nil, errors.New("sandbox: request field is too large")
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
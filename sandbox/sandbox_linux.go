@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package sandbox
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+const supported = true
+
+func setLimits(memoryLimit, cpuLimit uint64) error {
+	as := syscall.Rlimit{Cur: memoryLimit, Max: memoryLimit}
+	if err := syscall.Setrlimit(syscall.RLIMIT_AS, &as); err != nil {
+		return err
+	}
+
+	if cpuLimit != 0 {
+		cpu := syscall.Rlimit{Cur: cpuLimit, Max: cpuLimit}
+		if err := syscall.Setrlimit(syscall.RLIMIT_CPU, &cpu); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addressSpace returns the current size in bytes of the address space of the
+// process.
+func addressSpace() uint64 {
+	b, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+
+	pages, _, _ := bytes.Cut(b, []byte(" "))
+
+	n, err := strconv.ParseUint(string(pages), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return n * uint64(os.Getpagesize())
+}
@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build !linux
+
+package sandbox
+
+const supported = false
+
+func setLimits(_, _ uint64) error {
+	return ErrUnsupported
+}
+
+func addressSpace() uint64 {
+	return 0
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build linux
+
+package sandbox_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/sandbox"
+)
+
+const passphrase = "passphrase"
+
+func TestMain(m *testing.M) {
+	sandbox.Main()
+
+	os.Exit(m.Run())
+}
+
+func newDeriver() *sandbox.Deriver {
+	var d sandbox.Deriver
+
+	if raceEnabled {
+		d.MemoryLimit = math.MaxUint64
+	}
+
+	return &d
+}
+
+func TestDeriveKey(t *testing.T) {
+	t.Parallel()
+
+	d := newDeriver()
+
+	salt := make([]byte, 32)
+	params := abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}
+
+	k, err := d.DeriveKey(context.Background(), []byte(passphrase), salt, abcrypt.Argon2id, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := abcrypt.Argon2KeyDeriver{}.DeriveKey(context.Background(), []byte(passphrase), salt, abcrypt.Argon2id, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(k, expected) {
+		t.Error("unexpected mismatch between derived keys")
+	}
+}
+
+func TestDecryptWithDeriver(t *testing.T) {
+	t.Parallel()
+
+	dataEnc, err := os.ReadFile("../testdata/v1/argon2id/v0x13/data.txt.abcrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile("../testdata/data.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := abcrypt.DecryptWithOptions(context.Background(), dataEnc, []byte(passphrase), abcrypt.WithKeyDeriver(newDeriver()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(plaintext, data) {
+		t.Error("unexpected mismatch between plaintext and test data")
+	}
+}
+
+func TestDeriveKeyMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	if raceEnabled {
+		t.Skip("RLIMIT_AS is not applicable with the race detector")
+	}
+
+	d := sandbox.Deriver{MemoryLimit: 256 << 20}
+
+	salt := make([]byte, 32)
+	params := abcrypt.Params{MemoryCost: 1 << 20, TimeCost: 1, Parallelism: 1}
+
+	_, err := d.DeriveKey(context.Background(), []byte(passphrase), salt, abcrypt.Argon2id, params)
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	var sandboxError *sandbox.Error
+	if !errors.As(err, &sandboxError) {
+		t.Fatalf("unexpected error type: %v", err)
+	}
+}
+
+func TestDeriveKeyDefaultMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	if raceEnabled {
+		t.Skip("RLIMIT_AS is not applicable with the race detector")
+	}
+
+	var d sandbox.Deriver
+
+	salt := make([]byte, 32)
+	params := abcrypt.Params{MemoryCost: 2 * sandbox.DefaultMaxMemory / 1024, TimeCost: 1, Parallelism: 1}
+
+	_, err := d.DeriveKey(context.Background(), []byte(passphrase), salt, abcrypt.Argon2id, params)
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	var sandboxError *sandbox.Error
+	if !errors.As(err, &sandboxError) {
+		t.Fatalf("unexpected error type: %v", err)
+	}
+}
+
+func TestDeriveKeyCanceled(t *testing.T) {
+	t.Parallel()
+
+	d := newDeriver()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	salt := make([]byte, 32)
+	params := abcrypt.Params{MemoryCost: 1024, TimeCost: 1 << 20, Parallelism: 1}
+
+	_, err := d.DeriveKey(ctx, []byte(passphrase), salt, abcrypt.Argon2id, params)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected `%v`, got `%v`", context.DeadlineExceeded, err)
+	}
+}
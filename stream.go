@@ -0,0 +1,384 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/poly1305" //nolint:staticcheck // The payload is a single XChaCha20-Poly1305 message, so streaming requires an incremental Poly1305.
+)
+
+// streamChunkSize is the size of the buffer used by [Writer] and [Reader].
+const streamChunkSize = 64 * 1024
+
+var (
+	errOpen          = errors.New("chacha20poly1305: message authentication failed")
+	errWriterClosed  = errors.New("abcrypt: write to closed Writer")
+	errPayloadTooBig = errors.New("abcrypt: payload is too large")
+)
+
+// maxPayloadSize is the maximum size of the payload of XChaCha20-Poly1305.
+const maxPayloadSize = (1<<32 - 1) * 64
+
+// payloadCipher is the incremental form of XChaCha20-Poly1305 without the
+// associated data.
+type payloadCipher struct {
+	stream *chacha20.Cipher
+	mac    *poly1305.MAC
+	n      uint64
+}
+
+func newPayloadCipher(dk *derivedKey, nonce []byte) *payloadCipher {
+	stream, err := chacha20.NewUnauthenticatedCipher(dk.encrypt[:], nonce)
+	if err != nil {
+		panic(err)
+	}
+
+	// The first block of the key stream is the Poly1305 key, and the payload
+	// is encrypted from the second block.
+	var key [32]byte
+	stream.XORKeyStream(key[:], key[:])
+	stream.SetCounter(1)
+
+	c := payloadCipher{stream, poly1305.New(&key), 0}
+	clear(key[:])
+
+	return &c
+}
+
+func (c *payloadCipher) count(n int) error {
+	c.n += uint64(n)
+	if c.n > maxPayloadSize {
+		return errPayloadTooBig
+	}
+
+	return nil
+}
+
+func (c *payloadCipher) seal(dst, src []byte) error {
+	if err := c.count(len(src)); err != nil {
+		return err
+	}
+
+	c.stream.XORKeyStream(dst, src)
+	_, _ = c.mac.Write(dst[:len(src)])
+
+	return nil
+}
+
+func (c *payloadCipher) open(dst, src []byte) error {
+	if err := c.count(len(src)); err != nil {
+		return err
+	}
+
+	_, _ = c.mac.Write(src)
+	c.stream.XORKeyStream(dst, src)
+
+	return nil
+}
+
+func (c *payloadCipher) tag() []byte {
+	var pad [16]byte
+	if rem := c.n % 16; rem != 0 {
+		_, _ = c.mac.Write(pad[:16-rem])
+	}
+
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[8:], c.n)
+	_, _ = c.mac.Write(lengths[:])
+
+	return c.mac.Sum(nil)
+}
+
+// Writer is an [io.WriteCloser] which encrypts the data written to it in the
+// abcrypt encrypted data format.
+//
+// The output is identical to the output of [Encryptor], but the plaintext is
+// processed in chunks, so the memory usage does not depend on its size.
+type Writer struct {
+	w        io.Writer
+	header   *header
+	dk       *derivedKey
+	c        *payloadCipher
+	buf      []byte
+	observer Observer
+	pepperID string
+	err      error
+}
+
+// NewWriter creates a new [Writer] which writes the encrypted data to w, and
+// writes the header to w.
+//
+// The options are the same as [NewEncryptorWithOptions]. [Writer.Close] must
+// be called to write the MAC of the ciphertext.
+func NewWriter(ctx context.Context, w io.Writer, passphrase []byte, opts ...Option) (*Writer, error) {
+	return newWriter(ctx, w, passphrase, newOptions(opts))
+}
+
+func newWriter(ctx context.Context, w io.Writer, passphrase []byte, o *options) (*Writer, error) {
+	if err := o.checkEncrypt(); err != nil {
+		return nil, err
+	}
+
+	header := newHeader(o.argon2Type, defaultArgon2Version, o.memoryCost, o.timeCost, uint32(o.parallelism))
+
+	var (
+		pepperID string
+		pepper   []byte
+	)
+
+	if o.pepper != nil {
+		var err error
+
+		pepperID, pepper, err = currentPepper(o.pepper)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	derivedKey, err := deriveKey(ctx, o, OperationEncrypt, header, passphrase, pepper)
+	if err != nil {
+		return nil, err
+	}
+
+	header.computeMAC(derivedKey.mac[:])
+
+	b := header.asBytes()
+	if _, err := w.Write(b[:]); err != nil {
+		return nil, err
+	}
+
+	sw := Writer{w, header, derivedKey, newPayloadCipher(derivedKey, header.nonce[:]), make([]byte, streamChunkSize), o.observer, pepperID, nil}
+
+	return &sw, nil
+}
+
+// PepperID returns the ID of the pepper used for the key derivation, or an
+// empty string if no pepper was used.
+func (w *Writer) PepperID() string {
+	return w.pepperID
+}
+
+// Write encrypts p and writes the ciphertext to the underlying writer.
+//
+// This implements [io.Writer].
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := 0
+
+	for len(p) > 0 {
+		chunk := p[:min(len(p), len(w.buf))]
+
+		if err := w.c.seal(w.buf, chunk); err != nil {
+			w.err = err
+
+			return n, err
+		}
+
+		if _, err := w.w.Write(w.buf[:len(chunk)]); err != nil {
+			w.err = err
+
+			return n, err
+		}
+
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+
+	return n, nil
+}
+
+// Close writes the MAC of the ciphertext to the underlying writer. This does
+// not close the underlying writer.
+//
+// This implements [io.Closer].
+func (w *Writer) Close() error {
+	if w.err != nil {
+		if errors.Is(w.err, errWriterClosed) {
+			return nil
+		}
+
+		return w.err
+	}
+
+	w.err = errWriterClosed
+
+	if _, err := w.w.Write(w.c.tag()); err != nil {
+		return err
+	}
+
+	w.observer.BytesProcessed(OperationEncrypt, int(w.c.n))
+
+	return nil
+}
+
+// Reader is an [io.Reader] which decrypts the data in the abcrypt encrypted
+// data format.
+//
+// The plaintext is processed in chunks, so the memory usage does not depend on
+// its size. Since the MAC of the ciphertext is at the end of the data, the
+// plaintext returned by [Reader.Read] is not authenticated until it returns
+// [io.EOF]. If the MAC is invalid, it returns an [InvalidMACError] instead,
+// and the plaintext already read must be discarded.
+type Reader struct {
+	r        io.Reader
+	c        *payloadCipher
+	buf      []byte
+	pending  []byte
+	eof      bool
+	observer Observer
+	pepperID string
+	err      error
+}
+
+// NewReader creates a new [Reader] which reads the encrypted data from r. The
+// header is read and verified before this returns.
+//
+// The options are the same as [NewDecryptorWithOptions].
+func NewReader(ctx context.Context, r io.Reader, passphrase []byte, opts ...Option) (*Reader, error) {
+	return newReader(ctx, r, passphrase, newOptions(opts))
+}
+
+func newReader(ctx context.Context, r io.Reader, passphrase []byte, o *options) (*Reader, error) {
+	header, buf, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, pepperID, err := unlock(ctx, o, header, buf, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := header.verifyMAC(derivedKey.mac[:], buf[84:HeaderSize]); err != nil {
+		o.observer.HeaderMACFailure()
+
+		return nil, err
+	}
+
+	sr := newReaderWithKey(r, header, derivedKey, buf, o.observer)
+	sr.pepperID = pepperID
+
+	return sr, nil
+}
+
+// readHeader reads the header and the following TagSize bytes from r into a
+// new buffer for [Reader].
+func readHeader(r io.Reader) (*header, []byte, error) {
+	buf := make([]byte, streamChunkSize+HeaderSize+TagSize)
+
+	if _, err := io.ReadFull(r, buf[:HeaderSize+TagSize]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, nil, ErrInvalidLength
+		}
+
+		return nil, nil, err
+	}
+
+	header, err := parse(buf[:HeaderSize+TagSize])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return header, buf, nil
+}
+
+// newReaderWithKey creates a new [Reader] with the derived key already
+// verified against the header. buf is the buffer returned by readHeader.
+func newReaderWithKey(r io.Reader, header *header, dk *derivedKey, buf []byte, observer Observer) *Reader {
+	// The header is no longer needed, so the rest of the buffer holds the
+	// ciphertext, starting with the TagSize bytes already read.
+	buf = buf[HeaderSize:]
+
+	sr := Reader{
+		r:        r,
+		c:        newPayloadCipher(dk, header.nonce[:]),
+		buf:      buf,
+		pending:  buf[:TagSize],
+		observer: observer,
+	}
+
+	return &sr
+}
+
+// PepperID returns the ID of the pepper which authenticated the header, or an
+// empty string if no pepper was used.
+func (r *Reader) PepperID() string {
+	return r.pepperID
+}
+
+// Read decrypts the ciphertext into p.
+//
+// This implements [io.Reader].
+func (r *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		// The last TagSize bytes are held back, since they may be the MAC.
+		if len(r.pending) > TagSize {
+			n := min(len(p), len(r.pending)-TagSize)
+
+			if err := r.c.open(p[:n], r.pending[:n]); err != nil {
+				r.err = err
+
+				return 0, err
+			}
+
+			r.pending = r.pending[n:]
+
+			return n, nil
+		}
+
+		if r.eof {
+			r.finish()
+
+			continue
+		}
+
+		r.fill()
+	}
+}
+
+func (r *Reader) fill() {
+	n := copy(r.buf, r.pending)
+
+	m, err := r.r.Read(r.buf[n:])
+	r.pending = r.buf[:n+m]
+
+	switch {
+	case errors.Is(err, io.EOF):
+		r.eof = true
+	case err != nil:
+		r.err = err
+	}
+}
+
+func (r *Reader) finish() {
+	tag := r.c.tag()
+
+	if subtle.ConstantTimeCompare(tag, r.pending) != 1 {
+		r.observer.PayloadMACFailure()
+		r.err = &InvalidMACError{errOpen}
+
+		return
+	}
+
+	r.observer.BytesProcessed(OperationDecrypt, int(r.c.n))
+	r.err = io.EOF
+}
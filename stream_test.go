@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package abcrypt_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"testing/iotest"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+var streamOpts = []abcrypt.Option{abcrypt.WithParams(32, 3, 4)}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		"testdata/v1/argon2i/v0x13/data.txt.abcrypt",
+		"testdata/v1/argon2id/v0x13/data.txt.abcrypt",
+	} {
+		dataEnc, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile("testdata/data.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := abcrypt.NewReader(context.Background(), iotest.OneByteReader(bytes.NewReader(dataEnc)), []byte(passphrase))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plaintext, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(plaintext, data) {
+			t.Errorf("unexpected mismatch between plaintext and test data of `%v`", name)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 1, 15, 16, 17, 64*1024 - 1, 64 * 1024, 200*1024 + 3} {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+
+		var b bytes.Buffer
+
+		w, err := abcrypt.NewWriter(context.Background(), &b, []byte(passphrase), streamOpts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := io.Copy(w, iotest.HalfReader(bytes.NewReader(data))); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := b.Len(); n != abcrypt.HeaderSize+size+abcrypt.TagSize {
+			t.Errorf("expected ciphertext length `%v`, got `%v`", abcrypt.HeaderSize+size+abcrypt.TagSize, n)
+		}
+
+		plaintext, err := abcrypt.Decrypt(b.Bytes(), []byte(passphrase))
+		if err != nil {
+			t.Fatalf("size %v: %v", size, err)
+		}
+
+		if !bytes.Equal(plaintext, data) {
+			t.Errorf("unexpected mismatch between plaintext and data of size `%v`", size)
+		}
+
+		r, err := abcrypt.NewReader(context.Background(), &b, []byte(passphrase))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := iotest.TestReader(r, data); err != nil {
+			t.Errorf("size %v: %v", size, err)
+		}
+	}
+}
+
+func TestWriterClose(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+
+	w, err := abcrypt.NewWriter(context.Background(), &b, []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("expected no error, got `%v`", err)
+	}
+
+	if _, err := w.Write([]byte(data)); err == nil {
+		t.Error("expected error for write after close")
+	}
+
+	if n := b.Len(); n != abcrypt.HeaderSize+abcrypt.TagSize {
+		t.Errorf("expected ciphertext length `%v`, got `%v`", abcrypt.HeaderSize+abcrypt.TagSize, n)
+	}
+}
+
+func TestWriterInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+
+	var argon2TypeErr *abcrypt.InvalidArgon2TypeError
+	if _, err := abcrypt.NewWriter(context.Background(), &b, []byte(passphrase), abcrypt.WithArgon2Type(7)); !errors.As(err, &argon2TypeErr) {
+		t.Errorf("expected error type `%T`, got `%T`", argon2TypeErr, err)
+	}
+
+	var paramsErr *abcrypt.InvalidParamsError
+	if _, err := abcrypt.NewWriter(context.Background(), &b, []byte(passphrase), abcrypt.WithParams(32, 0, 1)); !errors.As(err, &paramsErr) {
+		t.Errorf("expected error type `%T`, got `%T`", paramsErr, err)
+	}
+
+	if n := b.Len(); n != 0 {
+		t.Errorf("expected no output, got `%v` bytes", n)
+	}
+}
+
+func TestReaderWithInvalidMAC(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext[abcrypt.HeaderSize] ^= 1
+
+	r, err := abcrypt.NewReader(context.Background(), bytes.NewReader(ciphertext), []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = io.ReadAll(r)
+
+	var invalidMACErr *abcrypt.InvalidMACError
+	if !errors.As(err, &invalidMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", invalidMACErr, err)
+	}
+}
+
+func TestReaderWithInvalidHeaderMAC(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = abcrypt.NewReader(context.Background(), bytes.NewReader(ciphertext), []byte("password"))
+
+	var headerMACErr *abcrypt.InvalidHeaderMACError
+	if !errors.As(err, &headerMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", headerMACErr, err)
+	}
+}
+
+func TestReaderWithInvalidLength(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), nil, []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := abcrypt.NewReader(context.Background(), bytes.NewReader(ciphertext[:len(ciphertext)-1]), []byte(passphrase)); !errors.Is(err, abcrypt.ErrInvalidLength) {
+		t.Errorf("expected error `%v`, got `%v`", abcrypt.ErrInvalidLength, err)
+	}
+}
+
+func TestReaderWithTruncatedData(t *testing.T) {
+	t.Parallel()
+
+	ciphertext, err := abcrypt.EncryptWithOptions(context.Background(), []byte(data), []byte(passphrase), streamOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := abcrypt.NewReader(context.Background(), bytes.NewReader(ciphertext[:len(ciphertext)-1]), []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = io.ReadAll(r)
+
+	var invalidMACErr *abcrypt.InvalidMACError
+	if !errors.As(err, &invalidMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", invalidMACErr, err)
+	}
+}
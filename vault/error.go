@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package vault
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClosed represents an error due to the vault was already closed.
+var ErrClosed = errors.New("vault: vault is closed")
+
+// ErrLockUnsupported represents an error due to file locking was not
+// supported on the platform.
+var ErrLockUnsupported = errors.New("vault: file locking is not supported on this platform")
+
+// UnknownVersionError represents an error due to the version of the contents
+// was the unrecognized version number.
+type UnknownVersionError struct {
+	// Version represents the obtained version number.
+	Version int
+}
+
+// Error returns a string representation of an [UnknownVersionError].
+func (e *UnknownVersionError) Error() string {
+	return fmt.Sprintf("vault: unknown version number `%v`", e.Version)
+}
+
+// NotFoundError represents an error due to the secret did not exist.
+type NotFoundError struct {
+	// Name represents the name of the secret.
+	Name string
+}
+
+// Error returns a string representation of a [NotFoundError].
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("vault: secret `%v` not found", e.Name)
+}
+
+// InvalidNameError represents an error due to the name of the secret was
+// empty, was not valid UTF-8 or contained a control character.
+type InvalidNameError struct {
+	// Name represents the obtained name.
+	Name string
+}
+
+// Error returns a string representation of an [InvalidNameError].
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("vault: invalid secret name %q", e.Name)
+}
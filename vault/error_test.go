@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package vault_test
+
+import (
+	"testing"
+
+	"github.com/sorairolake/abcrypt-go/vault"
+)
+
+func TestErrClosed(t *testing.T) {
+	t.Parallel()
+
+	err := vault.ErrClosed
+	expected := "vault: vault is closed"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestErrLockUnsupported(t *testing.T) {
+	t.Parallel()
+
+	err := vault.ErrLockUnsupported
+	expected := "vault: file locking is not supported on this platform"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestUnknownVersionError(t *testing.T) {
+	t.Parallel()
+
+	err := vault.UnknownVersionError{2}
+	expected := "vault: unknown version number `2`"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	err := vault.NotFoundError{"db/password"}
+	expected := "vault: secret `db/password` not found"
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
+
+func TestInvalidNameError(t *testing.T) {
+	t.Parallel()
+
+	err := vault.InvalidNameError{"a\nb"}
+	expected := `vault: invalid secret name "a\nb"`
+
+	if err.Error() != expected {
+		t.Error("unexpected error message")
+	}
+}
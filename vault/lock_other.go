@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package vault
+
+import "os"
+
+func tryLock(*os.File) (bool, error) {
+	return false, ErrLockUnsupported
+}
+
+func unlock(*os.File) error {
+	return ErrLockUnsupported
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package vault
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock locks f exclusively without blocking, and reports whether it was
+// locked.
+func tryLock(f *os.File) (bool, error) {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func unlock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
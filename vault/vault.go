@@ -0,0 +1,309 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package vault implements a store of named secrets in a single file
+// encrypted in the abcrypt encrypted data format.
+//
+// Storing many small secrets in separate files requires a key derivation for
+// each of them. A vault holds all of them in one file, so opening it runs
+// Argon2 once, and the secrets are read and modified in memory until
+// [Vault.Save] encrypts them again.
+//
+// The plaintext is a JSON object with the "version" member, which is 1, and
+// the "secrets" member, which maps the names of the secrets to their values
+// encoded in Base64.
+//
+// A vault is saved atomically with [abcrypt.EncryptFile], so the file can
+// always be read as a whole. To prevent concurrent writers from losing each
+// other's changes, an open vault holds an exclusive lock on the file with the
+// ".lock" extension next to it until [Vault.Close] is called. The lock file is
+// never removed.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sorairolake/abcrypt-go"
+)
+
+const version = 1
+
+// lockRetryInterval is the interval to retry acquiring the lock held by
+// another vault.
+const lockRetryInterval = 50 * time.Millisecond
+
+// contents represents the plaintext of a vault.
+type contents struct {
+	Version int               `json:"version"`
+	Secrets map[string][]byte `json:"secrets"`
+}
+
+// Vault represents an open vault.
+//
+// A Vault is not safe for concurrent use by multiple goroutines. The
+// concurrent use by multiple processes is serialized by the lock file.
+type Vault struct {
+	name       string
+	passphrase []byte
+	opts       []abcrypt.Option
+	lock       *os.File
+	secrets    map[string][]byte
+}
+
+// Create creates a new empty vault as the named file, and returns it open.
+//
+// If the file already exists, an error wrapping [fs.ErrExist] is returned.
+// opts are used to encrypt the vault, and are the same as
+// [abcrypt.NewEncryptorWithOptions].
+func Create(ctx context.Context, name string, passphrase []byte, opts ...abcrypt.Option) (*Vault, error) {
+	lock, err := lockFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	v := newVault(name, passphrase, opts, lock, make(map[string][]byte))
+
+	if _, err := os.Lstat(name); err == nil {
+		_ = v.Close()
+
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrExist}
+	}
+
+	if err := v.Save(ctx); err != nil {
+		_ = v.Close()
+
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Open opens the vault stored in the named file.
+//
+// This waits until no other vault holds the lock, or ctx is done. opts are
+// used to decrypt the vault and to encrypt it again, and are the same as
+// [abcrypt.NewDecryptorWithOptions]. The vault is saved with the Argon2 type
+// and the Argon2 parameters of the file unless opts specify them.
+func Open(ctx context.Context, name string, passphrase []byte, opts ...abcrypt.Option) (*Vault, error) {
+	lock, err := lockFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, fileOpts, err := load(ctx, name, passphrase, opts)
+	if err != nil {
+		_ = unlockFile(lock)
+
+		return nil, err
+	}
+
+	return newVault(name, passphrase, slices.Concat(fileOpts, opts), lock, secrets), nil
+}
+
+func newVault(name string, passphrase []byte, opts []abcrypt.Option, lock *os.File, secrets map[string][]byte) *Vault {
+	v := Vault{name, bytes.Clone(passphrase), opts, lock, secrets}
+
+	return &v
+}
+
+// load reads and decrypts the vault, and returns the secrets and the options
+// for the Argon2 type and the Argon2 parameters of the file.
+func load(ctx context.Context, name string, passphrase []byte, opts []abcrypt.Option) (map[string][]byte, []abcrypt.Option, error) {
+	ciphertext, err := os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, err := abcrypt.NewHeader(ciphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// This also ensures that the degree of parallelism fits in the option.
+	if err := header.CheckKDF(); err != nil {
+		return nil, nil, err
+	}
+
+	plaintext, err := abcrypt.DecryptWithOptions(ctx, ciphertext, passphrase, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer clear(plaintext)
+
+	var c contents
+	if err := json.Unmarshal(plaintext, &c); err != nil {
+		return nil, nil, fmt.Errorf("vault: invalid contents: %w", err)
+	}
+
+	if c.Version != version {
+		return nil, nil, &UnknownVersionError{c.Version}
+	}
+
+	if c.Secrets == nil {
+		c.Secrets = make(map[string][]byte)
+	}
+
+	fileOpts := []abcrypt.Option{
+		abcrypt.WithArgon2Type(header.Argon2Type),
+		abcrypt.WithParams(header.MemoryCost, header.TimeCost, uint8(header.Parallelism)),
+	}
+
+	return c.Secrets, fileOpts, nil
+}
+
+// Get returns a copy of the value of the named secret. If the secret does not
+// exist, a [NotFoundError] is returned.
+func (v *Vault) Get(name string) ([]byte, error) {
+	if v.lock == nil {
+		return nil, ErrClosed
+	}
+
+	value, ok := v.secrets[name]
+	if !ok {
+		return nil, &NotFoundError{name}
+	}
+
+	return bytes.Clone(value), nil
+}
+
+// Set sets the value of the named secret. The change is written to the file
+// by [Vault.Save].
+//
+// The name must be non-empty valid UTF-8 without control characters, or an
+// [InvalidNameError] is returned.
+func (v *Vault) Set(name string, value []byte) error {
+	if v.lock == nil {
+		return ErrClosed
+	}
+
+	if name == "" || !utf8.ValidString(name) || strings.ContainsFunc(name, unicode.IsControl) {
+		return &InvalidNameError{name}
+	}
+
+	if old, ok := v.secrets[name]; ok {
+		clear(old)
+	}
+
+	v.secrets[name] = bytes.Clone(value)
+
+	return nil
+}
+
+// Delete removes the named secret. If the secret does not exist, a
+// [NotFoundError] is returned. The change is written to the file by
+// [Vault.Save].
+func (v *Vault) Delete(name string) error {
+	if v.lock == nil {
+		return ErrClosed
+	}
+
+	value, ok := v.secrets[name]
+	if !ok {
+		return &NotFoundError{name}
+	}
+
+	clear(value)
+	delete(v.secrets, name)
+
+	return nil
+}
+
+// List returns the names of the secrets in lexicographical order.
+func (v *Vault) List() []string {
+	names := make([]string, 0, len(v.secrets))
+	for name := range v.secrets {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Save encrypts the secrets and writes them to the file atomically. The file
+// is created with mode 0600.
+func (v *Vault) Save(ctx context.Context) error {
+	if v.lock == nil {
+		return ErrClosed
+	}
+
+	plaintext, err := json.Marshal(contents{version, v.secrets})
+	if err != nil {
+		return err
+	}
+	defer clear(plaintext)
+
+	opts := slices.Concat(v.opts, []abcrypt.Option{abcrypt.WithOverwrite(), abcrypt.WithFileMode(0o600)})
+
+	return abcrypt.EncryptFile(ctx, v.name, bytes.NewReader(plaintext), v.passphrase, opts...)
+}
+
+// Close releases the lock, and clears the secrets and the passphrase from the
+// memory. The changes which have not been saved are discarded.
+func (v *Vault) Close() error {
+	if v.lock == nil {
+		return ErrClosed
+	}
+
+	for _, value := range v.secrets {
+		clear(value)
+	}
+
+	clear(v.secrets)
+	clear(v.passphrase)
+
+	err := unlockFile(v.lock)
+	v.lock = nil
+
+	return err
+}
+
+// lockFile acquires the exclusive lock of the vault, waiting until it is
+// released by the other vault or ctx is done.
+func lockFile(ctx context.Context, name string) (*os.File, error) {
+	f, err := os.OpenFile(name+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			_ = f.Close()
+
+			return nil, err
+		}
+
+		if locked {
+			return f, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = f.Close()
+
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+func unlockFile(f *os.File) error {
+	err := unlock(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
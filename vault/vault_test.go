@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: 2026 Shun Sakai
+//
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package vault_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sorairolake/abcrypt-go"
+	"github.com/sorairolake/abcrypt-go/vault"
+)
+
+const passphrase = "passphrase"
+
+var opts = []abcrypt.Option{abcrypt.WithParams(32, 3, 4)}
+
+func newVault(t *testing.T) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "secrets.abcrypt")
+
+	v, err := vault.Create(context.Background(), name, []byte(passphrase), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestVault(t *testing.T) {
+	t.Parallel()
+
+	name := newVault(t)
+
+	v, err := vault.Open(context.Background(), name, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"db/password", "api-key", "token"} {
+		if err := v.Set(s, []byte("value of "+s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := v.Delete("token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Save(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err = vault.Open(context.Background(), name, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+
+	if expected := []string{"api-key", "db/password"}; !slices.Equal(v.List(), expected) {
+		t.Errorf("expected names `%v`, got `%v`", expected, v.List())
+	}
+
+	if value, err := v.Get("db/password"); err != nil || string(value) != "value of db/password" {
+		t.Errorf("unexpected value `%s`: %v", value, err)
+	}
+
+	var notFoundErr *vault.NotFoundError
+	if _, err := v.Get("token"); !errors.As(err, &notFoundErr) {
+		t.Errorf("expected error type `%T`, got `%T`", notFoundErr, err)
+	}
+
+	if err := v.Delete("token"); !errors.As(err, &notFoundErr) {
+		t.Errorf("expected error type `%T`, got `%T`", notFoundErr, err)
+	}
+
+	ciphertext, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := abcrypt.NewParams(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (abcrypt.Params{MemoryCost: 32, TimeCost: 3, Parallelism: 4}); *params != expected {
+		t.Errorf("expected Argon2 parameters `%v`, got `%v`", expected, params)
+	}
+}
+
+func TestVaultInvalidName(t *testing.T) {
+	t.Parallel()
+
+	v, err := vault.Open(context.Background(), newVault(t), []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+
+	for _, name := range []string{"", "a\nb", "\xff"} {
+		var invalidNameErr *vault.InvalidNameError
+		if err := v.Set(name, nil); !errors.As(err, &invalidNameErr) {
+			t.Errorf("expected error type `%T` for `%q`, got `%T`", invalidNameErr, name, err)
+		}
+	}
+}
+
+func TestOpenUnsupported(t *testing.T) {
+	t.Parallel()
+
+	name := newVault(t)
+
+	ciphertext, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary.LittleEndian.PutUint32(ciphertext[24:28], 256)
+
+	if err := os.WriteFile(name, ciphertext, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var unsupportedKDFErr *abcrypt.UnsupportedKDFError
+	if _, err := vault.Open(context.Background(), name, []byte(passphrase)); !errors.As(err, &unsupportedKDFErr) {
+		t.Errorf("expected error type `%T`, got `%T`", unsupportedKDFErr, err)
+	}
+}
+
+func TestCreateExisting(t *testing.T) {
+	t.Parallel()
+
+	name := newVault(t)
+
+	if _, err := vault.Create(context.Background(), name, []byte(passphrase), opts...); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("expected error `%v`, got `%v`", fs.ErrExist, err)
+	}
+}
+
+func TestOpenWithInvalidPassphrase(t *testing.T) {
+	t.Parallel()
+
+	name := newVault(t)
+
+	var invalidHeaderMACErr *abcrypt.InvalidHeaderMACError
+	if _, err := vault.Open(context.Background(), name, []byte("password")); !errors.As(err, &invalidHeaderMACErr) {
+		t.Errorf("expected error type `%T`, got `%T`", invalidHeaderMACErr, err)
+	}
+
+	// The lock is released on failure.
+	v, err := vault.Open(context.Background(), name, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Close(); !errors.Is(err, vault.ErrClosed) {
+		t.Errorf("expected error `%v`, got `%v`", vault.ErrClosed, err)
+	}
+
+	if _, err := v.Get("a"); !errors.Is(err, vault.ErrClosed) {
+		t.Errorf("expected error `%v`, got `%v`", vault.ErrClosed, err)
+	}
+}
+
+func TestOpenLocked(t *testing.T) {
+	t.Parallel()
+
+	name := newVault(t)
+
+	v, err := vault.Open(context.Background(), name, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if _, err := vault.Open(ctx, name, []byte(passphrase)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error `%v`, got `%v`", context.DeadlineExceeded, err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	t.Parallel()
+
+	name := newVault(t)
+
+	const n = 4
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, n)
+
+	for i := range n {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v, err := vault.Open(context.Background(), name, []byte(passphrase))
+			if err != nil {
+				errs <- err
+
+				return
+			}
+			defer v.Close()
+
+			if err := v.Set(fmt.Sprint("secret", i), []byte("value")); err != nil {
+				errs <- err
+
+				return
+			}
+
+			errs <- v.Save(context.Background())
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := vault.Open(context.Background(), name, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+
+	if names := v.List(); len(names) != n {
+		t.Errorf("expected `%v` secrets, got `%v`", n, names)
+	}
+}